@@ -0,0 +1,290 @@
+package rod
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// AXNode is a node of the accessibility tree, it's what the screen readers see
+type AXNode struct {
+	ID          proto.AccessibilityAXNodeID
+	Role        string
+	Name        string
+	Description string
+	Value       string
+
+	// Ignored is true if the node is hidden from the screen readers
+	Ignored bool
+
+	// Properties are the states and attributes of the node, such as "checked", "disabled", "level"
+	Properties map[string]proto.JSON
+
+	// BackendNodeID of the DOM node that backs the AXNode, it's 0 if there's no DOM node
+	BackendNodeID proto.DOMBackendNodeID
+
+	Children []*AXNode
+
+	page *Page
+}
+
+// AccessibilityTreeE returns the accessibility tree of the page from the root web area.
+// The ignored nodes are excluded, their children will be lifted to their parents.
+func (p *Page) AccessibilityTreeE() (*AXNode, error) {
+	res, err := proto.AccessibilityGetFullAXTree{}.Call(p)
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[proto.AccessibilityAXNodeID]bool{}
+	for _, node := range res.Nodes {
+		for _, id := range node.ChildIds {
+			children[id] = true
+		}
+	}
+	for _, node := range res.Nodes {
+		if !children[node.NodeID] {
+			return newAXTree(p, res.Nodes, node.NodeID), nil
+		}
+	}
+
+	return nil, newErr(ErrAXNodeNotFound, res.Nodes)
+}
+
+// AXNodeE returns the node of the accessibility tree that the element backs, its descendants are included.
+// The node will be returned even if it's ignored, check the Ignored field for it.
+func (el *Element) AXNodeE() (*AXNode, error) {
+	desc, err := el.DescribeE(0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := el.partialAXTree(proto.AccessibilityGetPartialAXTree{ObjectID: el.ObjectID}, false)
+	if err != nil {
+		return nil, err
+	}
+	root := findAXNode(res.Nodes, desc.BackendNodeID)
+	if root == nil {
+		return el.axNodeFromFullTree(desc)
+	}
+
+	// walk the descendants level by level, the relatives of a node include its children
+	dict := map[proto.AccessibilityAXNodeID]*proto.AccessibilityAXNode{root.NodeID: root}
+	nodes := []*proto.AccessibilityAXNode{root}
+	for queue := []*proto.AccessibilityAXNode{root}; len(queue) > 0; queue = queue[1:] {
+		node := queue[0]
+
+		if hasMissingAXChild(dict, node) {
+			if node.BackendDOMNodeID == 0 {
+				return el.axNodeFromFullTree(desc)
+			}
+			res, err := el.partialAXTree(proto.AccessibilityGetPartialAXTree{BackendNodeID: node.BackendDOMNodeID}, true)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range res.Nodes {
+				if _, has := dict[n.NodeID]; !has {
+					dict[n.NodeID] = n
+					nodes = append(nodes, n)
+				}
+			}
+		}
+
+		for _, id := range node.ChildIds {
+			if child, has := dict[id]; has {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	return newAXTree(el.page, nodes, root.NodeID), nil
+}
+
+// partialAXTree is the proto.AccessibilityGetPartialAXTree that can send the fetchRelatives as false,
+// the one of the schema omits it when it's false, and the browser defaults it to true.
+type partialAXTree struct {
+	proto.AccessibilityGetPartialAXTree
+	FetchRelatives bool `json:"fetchRelatives"`
+}
+
+func (el *Element) partialAXTree(req proto.AccessibilityGetPartialAXTree, fetchRelatives bool) (*proto.AccessibilityGetPartialAXTreeResult, error) {
+	var res proto.AccessibilityGetPartialAXTreeResult
+	err := proto.Call(req.MethodName(), &partialAXTree{req, fetchRelatives}, &res, el)
+	return &res, err
+}
+
+// axNodeFromFullTree is the fallback of the AXNodeE when the subtree can't be walked via the partial trees
+func (el *Element) axNodeFromFullTree(desc *proto.DOMNode) (*AXNode, error) {
+	full, err := proto.AccessibilityGetFullAXTree{}.Call(el)
+	if err != nil {
+		return nil, err
+	}
+	if node := findAXNode(full.Nodes, desc.BackendNodeID); node != nil {
+		return newAXTree(el.page, full.Nodes, node.NodeID), nil
+	}
+	return nil, newErr(ErrAXNodeNotFound, desc)
+}
+
+func hasMissingAXChild(dict map[proto.AccessibilityAXNodeID]*proto.AccessibilityAXNode, node *proto.AccessibilityAXNode) bool {
+	for _, id := range node.ChildIds {
+		if _, has := dict[id]; !has {
+			return true
+		}
+	}
+	return false
+}
+
+// ElementE returns the element of the DOM node that backs the node
+func (n *AXNode) ElementE() (*Element, error) {
+	if n.BackendNodeID == 0 {
+		return nil, fmt.Errorf("%w: the accessibility node %s has no DOM node", newErr(ErrElementNotFound, n), n.ID)
+	}
+
+	n.page.enableNodeQuery()
+	res, err := proto.DOMPushNodesByBackendIdsToFrontend{
+		BackendNodeIds: []proto.DOMBackendNodeID{n.BackendNodeID},
+	}.Call(n.page)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.NodeIds) == 0 || res.NodeIds[0] == 0 {
+		return nil, fmt.Errorf("%w: the DOM node of %s is detached", newErr(ErrElementNotFound, n), n.ID)
+	}
+
+	return n.page.ElementFromNodeE(res.NodeIds[0])
+}
+
+// Find the first node in the tree, the node itself included, that has the role and the name.
+// Empty role or name matches any.
+func (n *AXNode) Find(role, name string) *AXNode {
+	list := n.findAll(role, name, true)
+	if len(list) == 0 {
+		return nil
+	}
+	return list[0]
+}
+
+// FindAll nodes in the tree, the node itself included, that have the role and the name.
+// Empty role or name matches any.
+func (n *AXNode) FindAll(role, name string) []*AXNode {
+	return n.findAll(role, name, false)
+}
+
+func (n *AXNode) findAll(role, name string, first bool) []*AXNode {
+	list := []*AXNode{}
+
+	var walk func(*AXNode) bool
+	walk = func(node *AXNode) bool {
+		if (role == "" || node.Role == role) && (name == "" || node.Name == name) {
+			list = append(list, node)
+			if first {
+				return true
+			}
+		}
+		for _, child := range node.Children {
+			if walk(child) {
+				return true
+			}
+		}
+		return false
+	}
+	walk(n)
+
+	return list
+}
+
+// String returns the tree as an indented outline of roles and names, such as:
+//
+//	RootWebArea "Title"
+//	  heading "Settings"
+//	  button "Save"
+func (n *AXNode) String() string {
+	lines := []string{}
+
+	var walk func(*AXNode, int)
+	walk = func(node *AXNode, depth int) {
+		line := strings.Repeat("  ", depth) + node.Role
+		if node.Name != "" {
+			line += fmt.Sprintf(" %q", node.Name)
+		}
+		if node.Value != "" {
+			line += fmt.Sprintf(" value=%q", node.Value)
+		}
+		lines = append(lines, line)
+
+		for _, child := range node.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(n, 0)
+
+	return strings.Join(lines, "\n")
+}
+
+// newAXTree builds the tree from the root, the ignored descendants are replaced with their children.
+// The children that are not in the nodes are skipped.
+func newAXTree(p *Page, nodes []*proto.AccessibilityAXNode, root proto.AccessibilityAXNodeID) *AXNode {
+	dict := map[proto.AccessibilityAXNodeID]*proto.AccessibilityAXNode{}
+	for _, node := range nodes {
+		dict[node.NodeID] = node
+	}
+
+	var build func(node *proto.AccessibilityAXNode) *AXNode
+	var children func(node *proto.AccessibilityAXNode) []*AXNode
+
+	children = func(node *proto.AccessibilityAXNode) []*AXNode {
+		list := []*AXNode{}
+		for _, id := range node.ChildIds {
+			child, has := dict[id]
+			if !has {
+				continue
+			}
+			if child.Ignored {
+				list = append(list, children(child)...)
+			} else {
+				list = append(list, build(child))
+			}
+		}
+		return list
+	}
+
+	build = func(node *proto.AccessibilityAXNode) *AXNode {
+		n := &AXNode{
+			ID:            node.NodeID,
+			Role:          axValue(node.Role),
+			Name:          axValue(node.Name),
+			Description:   axValue(node.Description),
+			Value:         axValue(node.Value),
+			Ignored:       node.Ignored,
+			Properties:    map[string]proto.JSON{},
+			BackendNodeID: node.BackendDOMNodeID,
+			page:          p,
+		}
+		for _, prop := range node.Properties {
+			if prop.Value != nil {
+				n.Properties[string(prop.Name)] = prop.Value.Value
+			}
+		}
+		n.Children = children(node)
+		return n
+	}
+
+	return build(dict[root])
+}
+
+func findAXNode(nodes []*proto.AccessibilityAXNode, id proto.DOMBackendNodeID) *proto.AccessibilityAXNode {
+	for _, node := range nodes {
+		if id != 0 && node.BackendDOMNodeID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+func axValue(v *proto.AccessibilityAXValue) string {
+	if v == nil {
+		return ""
+	}
+	return v.Value.String()
+}
@@ -0,0 +1,104 @@
+package rod
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func (s *S) TestAccessibilityTree() {
+	nodes := []*proto.AccessibilityAXNode{
+		{NodeID: "2", Ignored: true, ChildIds: []proto.AccessibilityAXNodeID{"3", "9"}},
+		{NodeID: "1", Role: cdptest.AXValue("RootWebArea"), Name: cdptest.AXValue("Title"), ChildIds: []proto.AccessibilityAXNodeID{"2", "4"}},
+		{NodeID: "3", Role: cdptest.AXValue("button"), Name: cdptest.AXValue("Save"), BackendDOMNodeID: 10, Properties: []*proto.AccessibilityAXProperty{
+			{Name: "focusable", Value: cdptest.AXValue(true)},
+		}},
+		{NodeID: "4", Role: cdptest.AXValue("heading"), Name: cdptest.AXValue("Settings"), BackendDOMNodeID: 11, ChildIds: []proto.AccessibilityAXNodeID{"5"}},
+		{NodeID: "5", Role: cdptest.AXValue("StaticText"), Name: cdptest.AXValue("Settings"), BackendDOMNodeID: 13, ChildIds: []proto.AccessibilityAXNodeID{"6"}},
+		{NodeID: "6", Role: cdptest.AXValue("InlineTextBox"), Name: cdptest.AXValue("Settings")},
+	}
+	other := &proto.AccessibilityAXNode{NodeID: "7", Role: cdptest.AXValue("link"), Name: cdptest.AXValue("Other"), BackendDOMNodeID: 12}
+	for _, node := range nodes {
+		for _, v := range []**proto.AccessibilityAXValue{&node.Role, &node.Name} {
+			if *v == nil {
+				*v = cdptest.AXValue(nil)
+			}
+		}
+	}
+
+	fake := newFake().
+		Handle("Accessibility.getFullAXTree", cdptest.Result(&proto.AccessibilityGetFullAXTreeResult{Nodes: nodes})).
+		Handle("Accessibility.getPartialAXTree", func(call *cdptest.Call) (interface{}, error) {
+			// the node itself, or the node with its parent and children
+			params := call.JSONParams()
+			list := map[string][]*proto.AccessibilityAXNode{
+				"el":      {nodes[2]},
+				"heading": {nodes[3]},
+				"other":   {other},
+				"11":      {nodes[1], nodes[3], nodes[4]},
+				"13":      {nodes[3], nodes[4], nodes[5]},
+			}[params.Get("objectId").String()+params.Get("backendNodeId").String()]
+			return &proto.AccessibilityGetPartialAXTreeResult{Nodes: list}, nil
+		}).
+		Handle("DOM.pushNodesByBackendIdsToFrontend", cdptest.Result(&proto.DOMPushNodesByBackendIdsToFrontendResult{
+			NodeIds: []proto.DOMNodeID{7},
+		})).
+		Handle("DOM.resolveNode", cdptest.Result(&proto.DOMResolveNodeResult{
+			Object: &proto.RuntimeRemoteObject{ObjectID: "el", Value: proto.NewJSON(nil)},
+		})).
+		Handle("DOM.describeNode", func(call *cdptest.Call) (interface{}, error) {
+			id := map[string]proto.DOMBackendNodeID{"el": 10, "heading": 11, "other": 12, "detached": 10}[call.JSONParams().Get("objectId").String()]
+			return &proto.DOMDescribeNodeResult{Node: &proto.DOMNode{BackendNodeID: id}}, nil
+		})
+
+	p := s.newFakePage(fake)
+
+	tree := p.AccessibilityTree()
+	s.Equal(strings.Join([]string{
+		`RootWebArea "Title"`,
+		`  button "Save"`,
+		`  heading "Settings"`,
+		`    StaticText "Settings"`,
+		`      InlineTextBox "Settings"`,
+	}, "\n"), tree.String())
+
+	button := tree.Find("button", "")
+	s.Equal("Save", button.Name)
+	s.True(button.Properties["focusable"].Bool())
+	s.Len(tree.FindAll("", "Settings"), 3)
+	s.Nil(tree.Find("link", ""))
+
+	el := button.Element()
+	s.Equal(proto.RuntimeRemoteObjectID("el"), el.ObjectID)
+	s.EqualValues(10, fake.Calls("DOM.pushNodesByBackendIdsToFrontend")[0].JSONParams().Get("backendNodeIds.0").Int())
+
+	_, err := tree.ElementE()
+	s.True(errors.Is(err, ErrElementNotFound))
+
+	fake.Reset()
+	node := el.AXNode()
+	s.Equal(proto.AccessibilityAXNodeID("3"), node.ID)
+	s.Equal(`{"objectId":"el","fetchRelatives":false}`, fake.Calls("Accessibility.getPartialAXTree")[0].JSONParams().Raw)
+
+	// the descendants are walked via the partial trees
+	s.Equal(strings.Join([]string{
+		`heading "Settings"`,
+		`  StaticText "Settings"`,
+		`    InlineTextBox "Settings"`,
+	}, "\n"), p.ElementFromObject("heading").AXNode().String())
+	s.Equal(4, fake.Called("Accessibility.getPartialAXTree"))
+	s.True(fake.Calls("Accessibility.getPartialAXTree")[2].JSONParams().Get("fetchRelatives").Bool())
+	s.Equal(0, fake.Called("Accessibility.getFullAXTree"))
+
+	s.Equal("Other", p.ElementFromObject("other").AXNode().Name)
+	s.Equal(0, fake.Called("Accessibility.getFullAXTree"))
+
+	// fall back to the full tree if the partial tree doesn't have the node
+	s.Equal(proto.AccessibilityAXNodeID("3"), p.ElementFromObject("detached").AXNode().ID)
+	s.Equal(1, fake.Called("Accessibility.getFullAXTree"))
+
+	_, err = p.ElementFromObject("none").AXNodeE()
+	s.True(errors.Is(err, ErrAXNodeNotFound))
+}
@@ -0,0 +1,326 @@
+package rod
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod/lib/utils"
+	"github.com/ysmood/kit"
+)
+
+// Severity of an accessibility violation
+type Severity string
+
+const (
+	// SeverityCritical blocks the users from the content
+	SeverityCritical Severity = "critical"
+	// SeveritySerious makes the content very hard to use
+	SeveritySerious Severity = "serious"
+	// SeverityModerate makes the content harder to use
+	SeverityModerate Severity = "moderate"
+	// SeverityMinor is an annoyance
+	SeverityMinor Severity = "minor"
+)
+
+// AccessibilityRule is a rule of the AuditAccessibilityE
+type AccessibilityRule string
+
+const (
+	// AccessibilityRuleImageAlt images must have alternate text
+	AccessibilityRuleImageAlt AccessibilityRule = "image-alt"
+	// AccessibilityRuleLabel form controls must have labels
+	AccessibilityRuleLabel AccessibilityRule = "label"
+	// AccessibilityRuleColorContrast text must have sufficient color contrast to the background
+	AccessibilityRuleColorContrast AccessibilityRule = "color-contrast"
+	// AccessibilityRuleButtonName buttons must have discernible text
+	AccessibilityRuleButtonName AccessibilityRule = "button-name"
+	// AccessibilityRuleLinkName links must have discernible text
+	AccessibilityRuleLinkName AccessibilityRule = "link-name"
+	// AccessibilityRuleDuplicateID ids must be unique
+	AccessibilityRuleDuplicateID AccessibilityRule = "duplicate-id"
+	// AccessibilityRuleHeadingOrder heading levels should only increase by one
+	AccessibilityRuleHeadingOrder AccessibilityRule = "heading-order"
+)
+
+// AccessibilityRules are all the rules of the AuditAccessibilityE, in the order of the report
+var AccessibilityRules = []AccessibilityRule{
+	AccessibilityRuleImageAlt,
+	AccessibilityRuleLabel,
+	AccessibilityRuleColorContrast,
+	AccessibilityRuleButtonName,
+	AccessibilityRuleLinkName,
+	AccessibilityRuleDuplicateID,
+	AccessibilityRuleHeadingOrder,
+}
+
+var accessibilitySeverities = map[AccessibilityRule]Severity{
+	AccessibilityRuleImageAlt:      SeverityCritical,
+	AccessibilityRuleLabel:         SeverityCritical,
+	AccessibilityRuleColorContrast: SeveritySerious,
+	AccessibilityRuleButtonName:    SeverityCritical,
+	AccessibilityRuleLinkName:      SeveritySerious,
+	AccessibilityRuleDuplicateID:   SeverityMinor,
+	AccessibilityRuleHeadingOrder:  SeverityModerate,
+}
+
+// the roles of the form controls that need labels
+var labeledRoles = map[string]bool{
+	"textbox":    true,
+	"searchbox":  true,
+	"combobox":   true,
+	"listbox":    true,
+	"checkbox":   true,
+	"radio":      true,
+	"spinbutton": true,
+	"slider":     true,
+	"switch":     true,
+}
+
+// AccessibilityViolation is a failure of an accessibility rule
+type AccessibilityViolation struct {
+	Rule     AccessibilityRule `json:"rule"`
+	Severity Severity          `json:"severity"`
+	Message  string            `json:"message"`
+
+	// Selector is the css selector path of the Element
+	Selector string `json:"selector"`
+
+	Element *Element `json:"-"`
+}
+
+// AccessibilityReport is the result of the AuditAccessibilityE
+type AccessibilityReport struct {
+	URL        string                    `json:"url"`
+	Violations []*AccessibilityViolation `json:"violations"`
+}
+
+// AuditAccessibilityE checks the page against the AccessibilityRules. The rules are based on the accessibility
+// tree, except the color-contrast and duplicate-id, which are based on the computed styles and the DOM.
+func (p *Page) AuditAccessibilityE() (*AccessibilityReport, error) {
+	url, err := p.EvalE(true, "", `location.href`, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := p.AccessibilityTreeE()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AccessibilityReport{URL: url.Value.String(), Violations: []*AccessibilityViolation{}}
+
+	add := func(rule AccessibilityRule, el *Element, msg string) error {
+		js, jsArgs := jsHelper("selectorPath", nil)
+		selector, err := el.EvalE(true, js, jsArgs)
+		if err != nil {
+			return err
+		}
+		report.Violations = append(report.Violations, &AccessibilityViolation{
+			Rule:     rule,
+			Severity: accessibilitySeverities[rule],
+			Message:  msg,
+			Selector: selector.Value.String(),
+			Element:  el,
+		})
+		return nil
+	}
+
+	for _, rule := range AccessibilityRules {
+		switch rule {
+		case AccessibilityRuleColorContrast:
+			err = p.auditContrast(add)
+
+		case AccessibilityRuleDuplicateID:
+			err = p.auditDuplicateIDs(add)
+
+		default:
+			nodes, messages := auditTree(tree, rule)
+			for i, node := range nodes {
+				var el *Element
+				el, err = node.ElementE()
+				if errors.Is(err, ErrElementNotFound) {
+					// the node has no DOM node, or its DOM node is detached after the tree is taken
+					err = nil
+					continue
+				} else if err != nil {
+					break
+				}
+				err = add(rule, el, messages[i])
+				if err != nil {
+					break
+				}
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// auditTree returns the nodes that violate the rule and the messages of them
+func auditTree(tree *AXNode, rule AccessibilityRule) (nodes []*AXNode, messages []string) {
+	violate := func(node *AXNode, msg string) {
+		if node.BackendNodeID != 0 {
+			nodes = append(nodes, node)
+			messages = append(messages, msg)
+		}
+	}
+
+	last := int64(0)
+	for _, node := range tree.FindAll("", "") {
+		switch rule {
+		case AccessibilityRuleImageAlt:
+			if (node.Role == "img" || node.Role == "image") && node.Name == "" {
+				violate(node, "image has no alternate text")
+			}
+
+		case AccessibilityRuleLabel:
+			if labeledRoles[node.Role] && node.Name == "" {
+				violate(node, fmt.Sprintf("%s has no label", node.Role))
+			}
+
+		case AccessibilityRuleButtonName:
+			if node.Role == "button" && node.Name == "" {
+				violate(node, "button has no discernible text")
+			}
+
+		case AccessibilityRuleLinkName:
+			if node.Role == "link" && node.Name == "" {
+				violate(node, "link has no discernible text")
+			}
+
+		case AccessibilityRuleHeadingOrder:
+			if node.Role != "heading" {
+				continue
+			}
+			level := node.Properties["level"].Int()
+			if last != 0 && level > last+1 {
+				violate(node, fmt.Sprintf("heading level jumps from %d to %d", last, level))
+			}
+			last = level
+		}
+	}
+
+	return
+}
+
+type addViolation func(rule AccessibilityRule, el *Element, msg string) error
+
+func (p *Page) auditContrast(add addViolation) error {
+	js, jsArgs := jsHelper("lowContrast", nil)
+	list, err := p.ElementsByJSE("", js, jsArgs)
+	if err != nil {
+		return err
+	}
+
+	for _, el := range list {
+		js, jsArgs := jsHelper("contrast", nil)
+		res, err := el.EvalE(true, js, jsArgs)
+		if err != nil {
+			return err
+		}
+		c := res.Value
+		msg := fmt.Sprintf("contrast ratio %v of %s on %s is lower than %v",
+			c.Get("ratio").Float(), c.Get("color").String(), c.Get("background").String(), c.Get("required").Float())
+
+		err = add(AccessibilityRuleColorContrast, el, msg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Page) auditDuplicateIDs(add addViolation) error {
+	js, jsArgs := jsHelper("duplicateIDs", nil)
+	list, err := p.ElementsByJSE("", js, jsArgs)
+	if err != nil {
+		return err
+	}
+
+	for _, el := range list {
+		id, err := el.EvalE(true, `this.id`, nil)
+		if err != nil {
+			return err
+		}
+
+		err = add(AccessibilityRuleDuplicateID, el, fmt.Sprintf("id %q is not unique", id.Value.String()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Filter returns the violations of the rule
+func (r *AccessibilityReport) Filter(rule AccessibilityRule) []*AccessibilityViolation {
+	list := []*AccessibilityViolation{}
+	for _, v := range r.Violations {
+		if v.Rule == rule {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// JSON of the report
+func (r *AccessibilityReport) JSON() []byte {
+	return kit.MustToJSONBytes(r)
+}
+
+// JUnit report for CI, each rule is a test case. The test case of a rule that has violations has a single
+// failure that lists all the violations, so the failures of the suite is the number of the failed rules.
+func (r *AccessibilityReport) JUnit() []byte {
+	type failure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+	type testCase struct {
+		ClassName string   `xml:"classname,attr"`
+		Name      string   `xml:"name,attr"`
+		Failure   *failure `xml:"failure,omitempty"`
+	}
+	type testSuite struct {
+		XMLName   xml.Name    `xml:"testsuite"`
+		Name      string      `xml:"name,attr"`
+		Tests     int         `xml:"tests,attr"`
+		Failures  int         `xml:"failures,attr"`
+		TestCases []*testCase `xml:"testcase"`
+	}
+
+	suite := &testSuite{Name: "accessibility " + r.URL, Tests: len(AccessibilityRules)}
+	for _, rule := range AccessibilityRules {
+		tc := &testCase{ClassName: "accessibility", Name: string(rule)}
+
+		list := r.Filter(rule)
+		if len(list) > 0 {
+			msg := fmt.Sprintf("%d violations", len(list))
+			if len(list) == 1 {
+				msg = "1 violation"
+			}
+
+			lines := []string{}
+			for _, v := range list {
+				lines = append(lines, v.Selector+": "+v.Message)
+			}
+			tc.Failure = &failure{
+				Message: msg,
+				Type:    string(accessibilitySeverities[rule]),
+				Text:    strings.Join(lines, "\n"),
+			}
+			suite.Failures++
+		}
+
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	utils.E(err)
+	return append([]byte(xml.Header), data...)
+}
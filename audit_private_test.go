@@ -0,0 +1,174 @@
+package rod
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod/lib/assets"
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/tidwall/gjson"
+)
+
+func (s *S) TestAuditAccessibility() {
+	level := func(l int) []*proto.AccessibilityAXProperty {
+		return []*proto.AccessibilityAXProperty{{Name: "level", Value: cdptest.AXValue(l)}}
+	}
+	nodes := []*proto.AccessibilityAXNode{
+		{NodeID: "1", Role: cdptest.AXValue("RootWebArea"), Name: cdptest.AXValue("Title"), ChildIds: []proto.AccessibilityAXNodeID{"2", "3", "4", "5", "6", "7", "8", "9", "10"}},
+		{NodeID: "2", Role: cdptest.AXValue("img"), Name: cdptest.AXValue(""), BackendDOMNodeID: 2},
+		{NodeID: "3", Role: cdptest.AXValue("textbox"), Name: cdptest.AXValue(""), BackendDOMNodeID: 3},
+		{NodeID: "4", Role: cdptest.AXValue("button"), Name: cdptest.AXValue(""), BackendDOMNodeID: 4},
+		{NodeID: "5", Role: cdptest.AXValue("link"), Name: cdptest.AXValue(""), BackendDOMNodeID: 5},
+		{NodeID: "6", Role: cdptest.AXValue("heading"), Name: cdptest.AXValue("Top"), BackendDOMNodeID: 6, Properties: level(1)},
+		{NodeID: "7", Role: cdptest.AXValue("heading"), Name: cdptest.AXValue("Deep"), BackendDOMNodeID: 7, Properties: level(3)},
+		{NodeID: "8", Role: cdptest.AXValue("button"), Name: cdptest.AXValue("OK"), BackendDOMNodeID: 8},
+
+		// the nodes whose elements can't be resolved are skipped
+		{NodeID: "9", Role: cdptest.AXValue("link"), Name: cdptest.AXValue("")},
+		{NodeID: "10", Role: cdptest.AXValue("img"), Name: cdptest.AXValue(""), BackendDOMNodeID: 10},
+	}
+
+	array := func(id proto.RuntimeRemoteObjectID) *proto.RuntimeRemoteObject {
+		return &proto.RuntimeRemoteObject{
+			Type: "object", Subtype: proto.RuntimeRemoteObjectSubtypeArray, ObjectID: id, Value: proto.NewJSON(nil),
+		}
+	}
+
+	// the functions are matched by the names of the js helpers
+	helper := func(name string) string {
+		js, _ := jsHelper(name, nil)
+		return SprintFnThis(js)
+	}
+
+	fake := newFake().
+		Handle("Accessibility.getFullAXTree", cdptest.Result(&proto.AccessibilityGetFullAXTreeResult{Nodes: nodes})).
+		Handle("DOM.pushNodesByBackendIdsToFrontend", func(call *cdptest.Call) (interface{}, error) {
+			id := call.JSONParams().Get("backendNodeIds.0").Int()
+			if id == 10 {
+				id = 0 // detached
+			}
+			return &proto.DOMPushNodesByBackendIdsToFrontendResult{NodeIds: []proto.DOMNodeID{proto.DOMNodeID(id)}}, nil
+		}).
+		Handle("DOM.resolveNode", func(call *cdptest.Call) (interface{}, error) {
+			id := proto.RuntimeRemoteObjectID(fmt.Sprintf("node%d", call.JSONParams().Get("nodeId").Int()))
+			return &proto.DOMResolveNodeResult{Object: &proto.RuntimeRemoteObject{ObjectID: id, Value: proto.NewJSON(nil)}}, nil
+		}).
+		Handle("DOM.describeNode", cdptest.Result(&proto.DOMDescribeNodeResult{Node: &proto.DOMNode{NodeName: "DIV"}})).
+		Handle("Runtime.getProperties", func(call *cdptest.Call) (interface{}, error) {
+			id := proto.RuntimeRemoteObjectID(call.JSONParams().Get("objectId").String() + "0")
+			return &proto.RuntimeGetPropertiesResult{Result: []*proto.RuntimePropertyDescriptor{{
+				Name: "0", Value: &proto.RuntimeRemoteObject{
+					Type: "object", Subtype: proto.RuntimeRemoteObjectSubtypeNode, ObjectID: id, Value: proto.NewJSON(nil),
+				},
+			}}}, nil
+		}).
+		Handle("Runtime.releaseObject", cdptest.Result(nil)).
+		Handle("Runtime.callFunctionOn", func(call *cdptest.Call) (interface{}, error) {
+			params := call.JSONParams()
+			fn := params.Get("functionDeclaration").String()
+			obj := &proto.RuntimeRemoteObject{Value: proto.NewJSON(nil)}
+			switch fn {
+			case assets.Helper:
+				obj.ObjectID = "helper"
+			case SprintFnThis(`location.href`):
+				obj.Value = proto.NewJSON("http://test.com")
+			case helper("selectorPath"):
+				obj.Value = proto.NewJSON("#" + params.Get("objectId").String())
+			case helper("lowContrast"):
+				obj = array("low")
+			case helper("duplicateIDs"):
+				obj = array("dup")
+			case helper("contrast"):
+				obj.Value = proto.NewJSON(map[string]interface{}{
+					"ratio": 1.5, "required": 4.5, "color": "rgb(200, 200, 200)", "background": "rgb(255, 255, 255)",
+				})
+			case SprintFnThis(`this.id`):
+				obj.Value = proto.NewJSON("a")
+			}
+			return &proto.RuntimeCallFunctionOnResult{Result: obj}, nil
+		})
+
+	p := s.newFakePage(fake)
+
+	report := p.AuditAccessibility()
+	s.Equal("http://test.com", report.URL)
+
+	list := []string{}
+	for _, v := range report.Violations {
+		list = append(list, fmt.Sprintf("%s %s %s %s", v.Rule, v.Severity, v.Selector, v.Message))
+	}
+	s.Equal([]string{
+		"image-alt critical #node2 image has no alternate text",
+		"label critical #node3 textbox has no label",
+		"color-contrast serious #low0 contrast ratio 1.5 of rgb(200, 200, 200) on rgb(255, 255, 255) is lower than 4.5",
+		"button-name critical #node4 button has no discernible text",
+		"link-name serious #node5 link has no discernible text",
+		`duplicate-id minor #dup0 id "a" is not unique`,
+		"heading-order moderate #node7 heading level jumps from 1 to 3",
+	}, list)
+
+	s.Equal(proto.RuntimeRemoteObjectID("node4"), report.Filter(AccessibilityRuleButtonName)[0].Element.ObjectID)
+	s.Len(report.Filter(AccessibilityRuleLinkName), 1)
+
+	data := gjson.ParseBytes(report.JSON())
+	s.Equal("http://test.com", data.Get("url").String())
+	s.Len(data.Get("violations").Array(), 7)
+	s.Equal("#node2", data.Get("violations.0.selector").String())
+	s.False(data.Get("violations.0.Element").Exists())
+
+	junit := string(report.JUnit())
+	s.True(strings.HasPrefix(junit, "<?xml"))
+	s.Contains(junit, `<testsuite name="accessibility http://test.com" tests="7" failures="7">`)
+	s.Contains(junit, `<failure message="1 violation" type="critical">#node2: image has no alternate text</failure>`)
+}
+
+func (s *S) TestAuditAccessibilityJUnit() {
+	type suite struct {
+		Tests     int `xml:"tests,attr"`
+		Failures  int `xml:"failures,attr"`
+		TestCases []struct {
+			Name     string `xml:"name,attr"`
+			Failures []struct {
+				Message string `xml:"message,attr"`
+				Type    string `xml:"type,attr"`
+				Text    string `xml:",chardata"`
+			} `xml:"failure"`
+		} `xml:"testcase"`
+	}
+	parse := func(r *AccessibilityReport) *suite {
+		res := &suite{}
+		s.Nil(xml.Unmarshal(r.JUnit(), res))
+		return res
+	}
+
+	empty := parse(&AccessibilityReport{URL: "http://a.com"})
+	s.Equal(len(AccessibilityRules), empty.Tests)
+	s.Equal(0, empty.Failures)
+	s.Len(empty.TestCases, len(AccessibilityRules))
+	for _, tc := range empty.TestCases {
+		s.Len(tc.Failures, 0)
+	}
+
+	res := parse(&AccessibilityReport{URL: "http://a.com", Violations: []*AccessibilityViolation{
+		{Rule: AccessibilityRuleLabel, Severity: SeverityCritical, Selector: "#a", Message: "a < b"},
+		{Rule: AccessibilityRuleLabel, Severity: SeverityCritical, Selector: "#b", Message: "b"},
+		{Rule: AccessibilityRuleDuplicateID, Severity: SeverityMinor, Selector: "#c", Message: "c"},
+	}})
+	s.Equal(2, res.Failures)
+	for _, tc := range res.TestCases {
+		switch AccessibilityRule(tc.Name) {
+		case AccessibilityRuleLabel:
+			s.Len(tc.Failures, 1)
+			s.Equal("2 violations", tc.Failures[0].Message)
+			s.Equal("critical", tc.Failures[0].Type)
+			s.Equal("#a: a < b\n#b: b", tc.Failures[0].Text)
+		case AccessibilityRuleDuplicateID:
+			s.Len(tc.Failures, 1)
+			s.Equal("minor", tc.Failures[0].Type)
+		default:
+			s.Len(tc.Failures, 0)
+		}
+	}
+}
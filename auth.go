@@ -0,0 +1,147 @@
+package rod
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+	"github.com/ysmood/kit"
+)
+
+// AuthCredentials of the HTTP authentication
+type AuthCredentials struct {
+	Username string
+	Password string
+}
+
+// AuthProvider returns the credentials for the challenge, returns nil to cancel the challenge
+type AuthProvider func(challenge *proto.FetchAuthChallenge) *AuthCredentials
+
+// AuthStore holds the credentials keyed by origin and realm, its Provide method can be used as an AuthProvider.
+// The challenges from unknown origins will be canceled.
+type AuthStore struct {
+	lock *sync.Mutex
+	list []*authEntry
+}
+
+type authEntry struct {
+	source      proto.FetchAuthChallengeSource
+	origin      string
+	realm       string
+	credentials *AuthCredentials
+}
+
+// NewAuthStore creates an empty store
+func NewAuthStore() *AuthStore {
+	return &AuthStore{lock: &sync.Mutex{}}
+}
+
+// Set the credentials for the server origin, such as "https://example.com".
+// If the realm is empty, the credentials will be used for all the realms of the origin.
+func (s *AuthStore) Set(origin, realm, username, password string) *AuthStore {
+	return s.add(proto.FetchAuthChallengeSourceServer, origin, realm, username, password)
+}
+
+// SetProxy sets the credentials for the proxy origin, such as "http://127.0.0.1:8080".
+// If the origin is empty, the credentials will be used for all the proxies.
+func (s *AuthStore) SetProxy(origin, username, password string) *AuthStore {
+	return s.add(proto.FetchAuthChallengeSourceProxy, origin, "", username, password)
+}
+
+func (s *AuthStore) add(source proto.FetchAuthChallengeSource, origin, realm, username, password string) *AuthStore {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.list = append(s.list, &authEntry{
+		source:      source,
+		origin:      strings.TrimSuffix(origin, "/"),
+		realm:       realm,
+		credentials: &AuthCredentials{Username: username, Password: password},
+	})
+	return s
+}
+
+// Provide the credentials for the challenge, the one with the same realm is preferred
+func (s *AuthStore) Provide(challenge *proto.FetchAuthChallenge) *AuthCredentials {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	source := challenge.Source
+	if source == "" {
+		source = proto.FetchAuthChallengeSourceServer
+	}
+	origin := strings.TrimSuffix(challenge.Origin, "/")
+
+	var fallback *AuthCredentials
+	for _, e := range s.list {
+		if e.source != source || (e.origin != "" && e.origin != origin) {
+			continue
+		}
+		if e.realm == challenge.Realm {
+			return e.credentials
+		}
+		if e.realm == "" && fallback == nil {
+			fallback = e.credentials
+		}
+	}
+	return fallback
+}
+
+// HandleAuthE makes the router answer every auth challenge, including the proxy ones, with the provider.
+// Because the router owns the Fetch domain of the session, use it instead of Browser.HandleAuthWith
+// when a router is already active on the same session.
+func (r *HijackRouter) HandleAuthE(provider AuthProvider) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.auth = provider
+	r.enable.HandleAuthRequests = provider != nil
+	return r.enable.Call(r.caller)
+}
+
+// HandleAuth makes the router answer every auth challenge with the provider
+func (r *HijackRouter) HandleAuth(provider AuthProvider) {
+	utils.E(r.HandleAuthE(provider))
+}
+
+// HandleAuthWithE creates a router to answer every auth challenge of the browser with the provider,
+// call the HijackRouter.Stop to stop it.
+func (b *Browser) HandleAuthWithE(provider AuthProvider) (*HijackRouter, error) {
+	r := b.HijackRequests()
+	err := r.HandleAuthE(provider)
+	if err != nil {
+		r.stopEvents()
+		return nil, err
+	}
+	go r.Run()
+	return r, nil
+}
+
+func (r *HijackRouter) handleAuth(e *proto.FetchAuthRequired) {
+	defer r.pending.Done()
+
+	r.lock.Lock()
+	provider := r.auth
+	r.lock.Unlock()
+
+	res := &proto.FetchAuthChallengeResponse{Response: proto.FetchAuthChallengeResponseResponseDefault}
+	if provider != nil {
+		res.Response = proto.FetchAuthChallengeResponseResponseCancelAuth
+		if c := provider(e.AuthChallenge); c != nil {
+			res.Response = proto.FetchAuthChallengeResponseResponseProvideCredentials
+			res.Username = c.Username
+			res.Password = c.Password
+		}
+	}
+
+	err := proto.FetchContinueWithAuth{
+		RequestID:             e.RequestID,
+		AuthChallengeResponse: res,
+	}.Call(r.caller)
+	if err != nil && err != context.Canceled {
+		log.Println(kit.C("[rod hijack err]", "yellow"), err)
+	}
+}
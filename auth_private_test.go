@@ -0,0 +1,86 @@
+package rod
+
+import (
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/tidwall/gjson"
+)
+
+func (s *S) TestHandleAuthWith() {
+	fake := newFake()
+	b := s.connectFake(fake)
+
+	store := NewAuthStore().
+		Set("http://a.com/", "", "a", "any").
+		Set("http://a.com", "admin", "a", "admin").
+		SetProxy("", "p", "proxy")
+
+	router := b.HijackRequests()
+	router.Add("http://a.com/mock", func(ctx *Hijack) {
+		ctx.Response.SetBody("ok")
+	})
+	router.HandleAuth(store.Provide)
+	go router.Run()
+
+	s.True(fake.Calls("Fetch.enable")[len(fake.Calls("Fetch.enable"))-1].JSONParams().Get("handleAuthRequests").Bool())
+
+	auth := func(id string, challenge *proto.FetchAuthChallenge) gjson.Result {
+		fake.Reset()
+		fake.Emit("other", "Fetch.authRequired", &proto.FetchAuthRequired{RequestID: "other", AuthChallenge: challenge})
+		fake.Emit("", "Fetch.authRequired", &proto.FetchAuthRequired{
+			RequestID: proto.FetchRequestID(id), AuthChallenge: challenge,
+		})
+		s.waitUntil(func() bool {
+			return fake.Called("Fetch.continueWithAuth") > 0
+		})
+		call := fake.Calls("Fetch.continueWithAuth")[0].JSONParams()
+		s.Equal(id, call.Get("requestId").String())
+		return call.Get("authChallengeResponse")
+	}
+
+	res := auth("1", &proto.FetchAuthChallenge{Origin: "http://a.com", Realm: "web"})
+	s.Equal("ProvideCredentials", res.Get("response").String())
+	s.Equal("any", res.Get("password").String())
+	s.Equal("admin", auth("2", &proto.FetchAuthChallenge{Origin: "http://a.com", Realm: "admin"}).Get("password").String())
+	s.Equal("CancelAuth", auth("3", &proto.FetchAuthChallenge{Origin: "http://b.com"}).Get("response").String())
+	s.Equal("proxy", auth("4", &proto.FetchAuthChallenge{
+		Source: proto.FetchAuthChallengeSourceProxy, Origin: "http://b.com",
+	}).Get("password").String())
+
+	// the requests that no handler takes will be continued
+	fake.Reset()
+	fake.Emit("", "Fetch.requestPaused", &proto.FetchRequestPaused{
+		RequestID: "5", Request: &proto.NetworkRequest{URL: "http://a.com/other"},
+	})
+	s.waitUntil(func() bool {
+		return fake.Called("Fetch.continueRequest") == 1
+	})
+
+	router.Stop()
+}
+
+func (s *S) TestHandleAuthStopConcurrently() {
+	fake := newFake()
+	b := s.connectFake(fake)
+
+	router := b.HijackRequests()
+	router.HandleAuth(func(*proto.FetchAuthChallenge) *AuthCredentials { return nil })
+	go router.Run()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			fake.Emit("", "Fetch.authRequired", &proto.FetchAuthRequired{
+				RequestID: "1", AuthChallenge: &proto.FetchAuthChallenge{},
+			})
+		}
+	}()
+
+	s.waitUntil(func() bool {
+		return fake.Called("Fetch.continueWithAuth") > 0
+	})
+	router.Stop()
+	<-done
+
+	s.True(router.stopped)
+}
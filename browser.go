@@ -49,14 +49,18 @@ type Browser struct {
 
 	monitorServer *kit.ServerContext
 
-	client  *cdp.Client
-	cdpCall CDPCall
-	event   *goob.Observable // all the browser events from cdp client
+	client      *cdp.Client
+	cdpCall     CDPCall
+	middlewares []CDPMiddleware
+	event       *goob.Observable // all the browser events from cdp client
 
 	// stores all the previous cdp call of same type. Browser doesn't have enough API
 	// for us to retrieve all its internal states. This is an workaround to map them to local.
 	// For example you can't use cdp API to get the current position of mouse.
 	states *sync.Map
+
+	// maps the original session id of a target to the current one, they differ after the cdp client reconnects
+	sessions *sync.Map
 }
 
 // New creates a controller
@@ -75,7 +79,8 @@ func New() *Browser {
 				Type: proto.EmulationScreenOrientationTypeLandscapePrimary,
 			},
 		},
-		states: &sync.Map{},
+		states:   &sync.Map{},
+		sessions: &sync.Map{},
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -169,7 +174,7 @@ func (b *Browser) ConnectE() (err error) {
 		}
 	}
 
-	b.client.Context(b.ctx, b.ctxCancel).Connect()
+	b.client.Context(b.ctx, b.ctxCancel).OnReconnect(b.recoverStates).Connect()
 
 	b.monitorServer = b.ServeMonitor(defaults.Monitor, !defaults.Blind)
 
@@ -333,11 +338,7 @@ func (b *Browser) waitEvent(ctx context.Context, sessionID proto.TargetSessionID
 
 // Call raw cdp interface directly
 func (b *Browser) Call(ctx context.Context, sessionID, methodName string, params json.RawMessage) (res []byte, err error) {
-	if b.cdpCall == nil {
-		res, err = b.client.Call(ctx, sessionID, methodName, params)
-	} else {
-		res, err = b.cdpCall(ctx, sessionID, methodName, params)
-	}
+	res, err = b.call(ctx, sessionID, methodName, params)
 	if err != nil {
 		return nil, err
 	}
@@ -347,6 +348,21 @@ func (b *Browser) Call(ctx context.Context, sessionID, methodName string, params
 	return
 }
 
+// call without recording the state
+func (b *Browser) call(ctx context.Context, sessionID, methodName string, params json.RawMessage) (res []byte, err error) {
+	sessionID = string(b.currentSession(proto.TargetSessionID(sessionID)))
+
+	call := b.cdpCall
+	if call == nil {
+		call = b.client.Call
+	}
+	for i := len(b.middlewares) - 1; i >= 0; i-- {
+		call = b.middlewares[i](call)
+	}
+
+	return call(ctx, sessionID, methodName, params)
+}
+
 // CallContext parameters for proto
 func (b *Browser) CallContext() (context.Context, proto.Client, string) {
 	return b.ctx, b, ""
@@ -366,9 +382,12 @@ func (b *Browser) PageFromTargetIDE(targetID proto.TargetTargetID) (*Page, error
 		lock:         &sync.Mutex{},
 		browser:      b,
 		TargetID:     targetID,
+		sessionLock:  &sync.RWMutex{},
 		executionIDs: map[proto.PageFrameID]proto.RuntimeExecutionContextID{},
 	}).Context(context.WithCancel(b.ctx))
 
+	page.console = newPageConsole(page.ctx)
+	page.frames = newPageFrames(page.ctx)
 	page.Mouse = &Mouse{lock: &sync.Mutex{}, page: page, id: kit.RandString(8)}
 	page.Keyboard = &Keyboard{lock: &sync.Mutex{}, page: page}
 
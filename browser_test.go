@@ -1,8 +1,11 @@
 package rod_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"net/http"
 	"runtime"
 	"strings"
 	"sync"
@@ -11,6 +14,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/rod/lib/utils"
@@ -30,6 +34,27 @@ func (s *S) TestIncognito() {
 	s.EqualValues(1, page.Eval(`k => localStorage[k]`, k).Int())
 }
 
+func (s *S) TestPool() {
+	u, close := serveStatic()
+	defer close()
+
+	pool := rod.NewIncognitoPool(s.browser, 2).MaxUses(2)
+	defer pool.Close()
+
+	page := pool.Get(context.Background())
+	page.Navigate(u + "fixtures/click.html")
+	page.Eval(`() => localStorage.k = 1`)
+	page.Viewport(100, 100, 1, false)
+	pool.Put(page)
+
+	page = pool.Get(context.Background())
+	s.Equal("about:blank", page.Info().URL)
+	page.Navigate(u + "fixtures/click.html")
+	s.Nil(page.Eval(`() => localStorage.k`).Value())
+	s.EqualValues(800, page.Eval(`() => innerWidth`).Int())
+	pool.Put(page)
+}
+
 func (s *S) TestBrowserPages() {
 	page := s.browser.Page(srcFile("fixtures/click.html")).WaitLoad()
 	defer page.Close()
@@ -141,6 +166,63 @@ func (s *S) TestRemoteLaunch() {
 	s.True(p.Has("[a=ok]"))
 }
 
+type breakableWs struct {
+	cdp.DefaultWsClient
+	conn cdp.WebsocketableConn
+}
+
+func (ws *breakableWs) Connect(ctx context.Context, url string, header http.Header) (cdp.WebsocketableConn, error) {
+	conn, err := ws.DefaultWsClient.Connect(ctx, url, header)
+	ws.conn = conn
+	return conn, err
+}
+
+func (s *S) TestBrowserReconnect() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	ws := &breakableWs{DefaultWsClient: *cdp.NewDefaultWsClient()}
+	reconnected := make(chan kit.Nil)
+	c := cdp.New(launcher.New().Launch()).Websocket(ws).Reconnect(&cdp.ReconnectPolicy{}).
+		OnReconnect(func() { close(reconnected) })
+	b := rod.New().Context(ctx, cancel).Client(c).Connect()
+	defer b.Close()
+
+	p := b.Page(srcFile("fixtures/click.html"))
+	s.EqualValues(1, p.Eval(`1`).Int())
+
+	utils.E(ws.conn.(io.Closer).Close())
+	<-reconnected
+
+	utils.E(kit.Retry(ctx, rod.Sleeper(), func() (bool, error) {
+		_, err := p.EvalE(true, "", `1`, nil)
+		return err == nil, nil
+	}))
+	p.Element("button").Click()
+	s.True(p.Has("[a=ok]"))
+}
+
+func (s *S) TestBrowserRecordReplay() {
+	trace := bytes.NewBuffer(nil)
+
+	run := func(ws cdp.Websocketable, u string) string {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		c := cdp.New(u).Websocket(ws)
+		b := rod.New().Context(ctx, cancel).Client(c).Connect()
+		return b.Page(srcFile("fixtures/click.html")).Element("button").Text()
+	}
+
+	l := launcher.New()
+	defer func() { _ = kit.KillTree(l.PID()) }()
+	s.Equal("click me", run(cdp.NewRecorder(nil, trace), l.Launch()))
+
+	replayer, err := cdp.NewReplayer(trace)
+	utils.E(err)
+	s.Equal("click me", run(replayer, ""))
+}
+
 func (s *S) TestTrace() {
 	msg := ""
 	var errs []error
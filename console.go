@@ -0,0 +1,366 @@
+package rod
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// ConsoleMessage is a message logged by the page via the console API, or an entry of the browser log
+type ConsoleMessage struct {
+	// Source is "console" for the console API calls, else it's the source of the browser log, such as "network"
+	Source string
+	// Type of the console API call, such as "log" or "error", or the level of the browser log, such as "warning"
+	Type string
+	// Text of the message, the arguments are joined with spaces
+	Text string
+	// Args of the console API call, they are converted to Go values via the ObjectToJSONE
+	Args []proto.JSON
+
+	URL        string
+	LineNumber int64
+	StackTrace *proto.RuntimeStackTrace
+	Timestamp  time.Time
+}
+
+// JSException is an exception thrown by the js of the page
+type JSException struct {
+	// Text of the exception, such as "Uncaught"
+	Text string
+	// Description of the thrown value, for an Error object it includes the message and the stack
+	Description string
+	// Value of the thrown value if it's not an object, such as `throw 1`
+	Value proto.JSON
+
+	URL          string
+	LineNumber   int64 // 0-based
+	ColumnNumber int64 // 0-based
+	StackTrace   *proto.RuntimeStackTrace
+	Timestamp    time.Time
+}
+
+// Error interface
+func (e *JSException) Error() string {
+	desc := e.Description
+	if desc == "" {
+		desc = e.Value.String()
+	}
+	return strings.TrimSpace(e.Text + " " + desc)
+}
+
+// TestingT is the interface that FailOnExceptionE reports to, such as the *testing.T
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// ConsoleMessagesE returns the console messages of the page and its out-of-process iframes.
+// The capturing starts on the first call of the console related methods, the messages logged before
+// that will be reported asynchronously by the browser.
+// The messages are kept until the ClearConsoleMessages is called or the page is closed.
+func (p *Page) ConsoleMessagesE() ([]*ConsoleMessage, error) {
+	err := p.startConsole()
+	if err != nil {
+		return nil, err
+	}
+
+	c := p.console
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]*ConsoleMessage{}, c.messages...), nil
+}
+
+// ClearConsoleMessages removes the captured console messages, the capturing keeps going
+func (p *Page) ClearConsoleMessages() {
+	c := p.console
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.messages = []*ConsoleMessage{}
+}
+
+// OnConsoleE calls the fn for every console message of the page, call the remove to stop it
+func (p *Page) OnConsoleE(fn func(*ConsoleMessage)) (remove func(), err error) {
+	c := p.console
+	c.lock.Lock()
+	id := c.add()
+	c.onConsole[id] = fn
+	c.lock.Unlock()
+
+	remove = func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		delete(c.onConsole, id)
+	}
+
+	err = p.startConsole()
+	if err != nil {
+		remove()
+		return nil, err
+	}
+	return remove, nil
+}
+
+// OnExceptionE calls the fn for every uncaught exception of the page, call the remove to stop it
+func (p *Page) OnExceptionE(fn func(*JSException)) (remove func(), err error) {
+	c := p.console
+	c.lock.Lock()
+	id := c.add()
+	c.onException[id] = fn
+	c.lock.Unlock()
+
+	remove = func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		delete(c.onException, id)
+	}
+
+	err = p.startConsole()
+	if err != nil {
+		remove()
+		return nil, err
+	}
+	return remove, nil
+}
+
+// FailOnExceptionE reports every uncaught exception of the page to the t as an error, call the remove to stop it
+func (p *Page) FailOnExceptionE(t TestingT) (remove func(), err error) {
+	return p.OnExceptionE(func(e *JSException) {
+		t.Errorf("[rod] uncaught exception: %s (%s:%d:%d)", e.Error(), e.URL, e.LineNumber+1, e.ColumnNumber+1)
+	})
+}
+
+type pageConsole struct {
+	ctx       context.Context // the context of the page, the capturing stops when it's done
+	lock      *sync.Mutex
+	startLock *sync.Mutex // serializes the startConsole, the lock isn't held while the domains are being enabled
+
+	started     bool
+	messages    []*ConsoleMessage
+	nextID      int
+	onConsole   map[int]func(*ConsoleMessage)
+	onException map[int]func(*JSException)
+}
+
+func newPageConsole(ctx context.Context) *pageConsole {
+	return &pageConsole{
+		ctx:         ctx,
+		lock:        &sync.Mutex{},
+		startLock:   &sync.Mutex{},
+		messages:    []*ConsoleMessage{},
+		onConsole:   map[int]func(*ConsoleMessage){},
+		onException: map[int]func(*JSException){},
+	}
+}
+
+func (c *pageConsole) isStarted() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.started
+}
+
+func (c *pageConsole) setStarted(started bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.started = started
+}
+
+func (c *pageConsole) add() int {
+	c.nextID++
+	return c.nextID
+}
+
+// startConsole enables the Runtime and Log domains of the page and its out-of-process iframes,
+// the browser will report the existing messages once the domains are enabled.
+func (p *Page) startConsole() error {
+	// the out-of-process iframes must be tracked before their sessions are listed
+	err := p.startFrames()
+	if err != nil {
+		return err
+	}
+
+	c := p.console
+	c.startLock.Lock()
+	defer c.startLock.Unlock()
+
+	if c.isStarted() {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	root := p.Root().Context(ctx, cancel)
+	s := p.browser.event.Subscribe(ctx)
+
+	// mark it as started before the sessions are listed, so that the iframes attached meanwhile
+	// will enable the domains by themselves
+	c.setStarted(true)
+
+	sessions := []proto.TargetSessionID{root.sessionID()}
+	for _, id := range root.frameSessionList() {
+		sessions = append(sessions, id)
+	}
+	for _, id := range sessions {
+		err := root.withSession(id).enableConsole()
+		if err != nil {
+			c.setStarted(false)
+			cancel()
+			return err
+		}
+	}
+
+	go func() {
+		defer cancel()
+
+		for msg := range s {
+			e := msg.(*cdp.Event)
+			if root.ownsSession(proto.TargetSessionID(e.SessionID)) {
+				root.withSession(proto.TargetSessionID(e.SessionID)).handleConsole(e)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (p *Page) enableConsole() error {
+	err := proto.RuntimeEnable{}.Call(p)
+	if err != nil {
+		return err
+	}
+	return proto.LogEnable{}.Call(p)
+}
+
+func (p *Page) handleConsole(e *cdp.Event) {
+	switch e.Method {
+	case (proto.RuntimeConsoleAPICalled{}).MethodName():
+		var evt proto.RuntimeConsoleAPICalled
+		if !Event(e, &evt) {
+			return
+		}
+
+		msg := &ConsoleMessage{
+			Source:     "console",
+			Type:       string(evt.Type),
+			StackTrace: evt.StackTrace,
+			Timestamp:  runtimeTime(evt.Timestamp),
+		}
+		msg.Args, msg.Text = p.consoleArgs(evt.Args)
+		if evt.StackTrace != nil && len(evt.StackTrace.CallFrames) > 0 {
+			msg.URL = evt.StackTrace.CallFrames[0].URL
+			msg.LineNumber = evt.StackTrace.CallFrames[0].LineNumber
+		}
+		p.console.addMessage(msg)
+
+	case (proto.LogEntryAdded{}).MethodName():
+		var evt proto.LogEntryAdded
+		if !Event(e, &evt) {
+			return
+		}
+
+		msg := &ConsoleMessage{
+			Source:     string(evt.Entry.Source),
+			Type:       string(evt.Entry.Level),
+			Text:       evt.Entry.Text,
+			URL:        evt.Entry.URL,
+			LineNumber: evt.Entry.LineNumber,
+			StackTrace: evt.Entry.StackTrace,
+			Timestamp:  runtimeTime(evt.Entry.Timestamp),
+		}
+		if len(evt.Entry.Args) > 0 {
+			msg.Args, _ = p.consoleArgs(evt.Entry.Args)
+		}
+		p.console.addMessage(msg)
+
+	case (proto.RuntimeExceptionThrown{}).MethodName():
+		var evt proto.RuntimeExceptionThrown
+		if !Event(e, &evt) {
+			return
+		}
+
+		exp := newJSException(evt.ExceptionDetails)
+		exp.Timestamp = runtimeTime(evt.Timestamp)
+		p.console.addException(exp)
+	}
+}
+
+// consoleArgs converts the arguments to Go values, the ones that can't be serialized will be their descriptions
+func (p *Page) consoleArgs(args []*proto.RuntimeRemoteObject) ([]proto.JSON, string) {
+	list := []proto.JSON{}
+	texts := []string{}
+
+	for _, arg := range args {
+		val, err := p.ObjectToJSONE(arg)
+		if err != nil {
+			val = proto.NewJSON(arg.Description)
+		}
+		list = append(list, val)
+
+		// the browser keeps the arguments alive until they are released
+		if arg.ObjectID != "" {
+			_ = p.ReleaseE(arg.ObjectID)
+		}
+
+		switch {
+		case arg.Type == proto.RuntimeRemoteObjectTypeString:
+			texts = append(texts, arg.Value.String())
+		case arg.UnserializableValue != "":
+			texts = append(texts, string(arg.UnserializableValue))
+		case arg.Description != "":
+			texts = append(texts, arg.Description)
+		default:
+			texts = append(texts, fmt.Sprint(arg.Value.Value()))
+		}
+	}
+
+	return list, strings.Join(texts, " ")
+}
+
+func (c *pageConsole) addMessage(msg *ConsoleMessage) {
+	c.lock.Lock()
+	c.messages = append(c.messages, msg)
+	list := []func(*ConsoleMessage){}
+	for _, fn := range c.onConsole {
+		list = append(list, fn)
+	}
+	c.lock.Unlock()
+
+	for _, fn := range list {
+		fn(msg)
+	}
+}
+
+func (c *pageConsole) addException(exp *JSException) {
+	c.lock.Lock()
+	list := []func(*JSException){}
+	for _, fn := range c.onException {
+		list = append(list, fn)
+	}
+	c.lock.Unlock()
+
+	for _, fn := range list {
+		fn(exp)
+	}
+}
+
+func newJSException(details *proto.RuntimeExceptionDetails) *JSException {
+	exp := &JSException{
+		Text:         details.Text,
+		URL:          details.URL,
+		LineNumber:   details.LineNumber,
+		ColumnNumber: details.ColumnNumber,
+		StackTrace:   details.StackTrace,
+	}
+	if details.Exception != nil {
+		exp.Description = details.Exception.Description
+		exp.Value = details.Exception.Value
+	}
+	return exp
+}
+
+// runtimeTime converts the milliseconds since epoch to time
+func runtimeTime(t proto.RuntimeTimestamp) time.Time {
+	return time.Unix(0, int64(float64(t)*float64(time.Millisecond)))
+}
@@ -0,0 +1,103 @@
+package rod
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+type fakeT struct {
+	lock *sync.Mutex
+	errs []string
+}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+func (s *S) TestConsole() {
+	fake := newFake().
+		Handle("Runtime.callFunctionOn", cdptest.Result(&proto.RuntimeCallFunctionOnResult{
+			Result: &proto.RuntimeRemoteObject{Type: "object", Value: proto.NewJSON(map[string]int{"a": 1})},
+		}))
+
+	p := s.newFakePage(fake)
+
+	messages := make(chan *ConsoleMessage, 10)
+	exceptions := make(chan *JSException, 10)
+	removeConsole := p.OnConsole(func(msg *ConsoleMessage) { messages <- msg })
+	p.OnException(func(e *JSException) { exceptions <- e })
+	t := &fakeT{lock: &sync.Mutex{}}
+	p.FailOnException(t)
+
+	s.Equal("s", fake.Calls("Runtime.enable")[0].SessionID)
+	s.Equal("s", fake.Calls("Log.enable")[0].SessionID)
+
+	fake.Emit("other", "Runtime.consoleAPICalled", &proto.RuntimeConsoleAPICalled{Type: "log"})
+	fake.Emit("s", "Runtime.consoleAPICalled", &proto.RuntimeConsoleAPICalled{
+		Type: proto.RuntimeConsoleAPICalledTypeWarning,
+		Args: []*proto.RuntimeRemoteObject{
+			{Type: proto.RuntimeRemoteObjectTypeString, Value: proto.NewJSON("hi")},
+			{Type: proto.RuntimeRemoteObjectTypeNumber, Value: proto.NewJSON(1), Description: "1"},
+			{Type: proto.RuntimeRemoteObjectTypeObject, ObjectID: "obj", Description: "Object", Value: proto.NewJSON(nil)},
+		},
+		Timestamp:  1500,
+		StackTrace: &proto.RuntimeStackTrace{CallFrames: []*proto.RuntimeCallFrame{{URL: "a.js", LineNumber: 2}}},
+	})
+	msg := <-messages
+	s.Equal("console", msg.Source)
+	s.Equal("warning", msg.Type)
+	s.Equal("hi 1 Object", msg.Text)
+	s.EqualValues(1, msg.Args[2].Get("a").Int())
+	s.Equal("a.js", msg.URL)
+	s.Equal(time.Unix(1, 500*int64(time.Millisecond)), msg.Timestamp)
+	s.Equal("obj", fake.Calls("Runtime.releaseObject")[0].JSONParams().Get("objectId").String())
+
+	fake.Emit("s", "Log.entryAdded", &proto.LogEntryAdded{Entry: &proto.LogLogEntry{
+		Source: proto.LogLogEntrySourceNetwork, Level: proto.LogLogEntryLevelError, Text: "404",
+	}})
+	msg = <-messages
+	s.Equal("network", msg.Source)
+	s.Equal("error", msg.Type)
+	s.Equal("404", msg.Text)
+
+	s.Len(p.ConsoleMessages(), 2)
+
+	fake.Emit("s", "Runtime.exceptionThrown", &proto.RuntimeExceptionThrown{ExceptionDetails: &proto.RuntimeExceptionDetails{
+		Text: "Uncaught", URL: "a.js", LineNumber: 1, ColumnNumber: 2,
+		Exception: &proto.RuntimeRemoteObject{
+			Type: proto.RuntimeRemoteObjectTypeObject, Description: "Error: boom", Value: proto.NewJSON(nil),
+		},
+	}})
+	exp := <-exceptions
+	s.Equal("Uncaught Error: boom", exp.Error())
+	s.waitUntil(func() bool {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return len(t.errs) == 1
+	})
+	s.Equal("[rod] uncaught exception: Uncaught Error: boom (a.js:2:3)", t.errs[0])
+
+	removeConsole()
+	fake.Emit("s", "Runtime.exceptionThrown", &proto.RuntimeExceptionThrown{ExceptionDetails: &proto.RuntimeExceptionDetails{
+		Exception: &proto.RuntimeRemoteObject{Type: proto.RuntimeRemoteObjectTypeNumber, Value: proto.NewJSON(1)},
+	}})
+	s.Equal("1", (<-exceptions).Error())
+	fake.Emit("s", "Runtime.consoleAPICalled", &proto.RuntimeConsoleAPICalled{Type: "log"})
+	s.waitUntil(func() bool {
+		return len(p.ConsoleMessages()) == 3
+	})
+	s.Len(messages, 0)
+
+	p.ClearConsoleMessages()
+	s.Len(p.ConsoleMessages(), 0)
+	fake.Emit("s", "Runtime.consoleAPICalled", &proto.RuntimeConsoleAPICalled{Type: "log"})
+	s.waitUntil(func() bool {
+		return len(p.ConsoleMessages()) == 1
+	})
+}
@@ -33,10 +33,10 @@ func (b *Browser) CancelTimeout() *Browser {
 
 // Context creates a clone with a context that inherits the previous one
 func (p *Page) Context(ctx context.Context, cancel func()) *Page {
-	newObj := *p
+	newObj := p.clone()
 	newObj.ctx = ctx
 	newObj.ctxCancel = cancel
-	return &newObj
+	return newObj
 }
 
 // GetContext returns the current context
@@ -0,0 +1,278 @@
+package rod
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/kit"
+)
+
+// DownloadManager saves the downloads of the browser context into a directory,
+// each file is named after the GUID of the download until it's moved by the user.
+type DownloadManager struct {
+	browser   *Browser
+	sessionID proto.TargetSessionID // empty means all the pages of the browser context
+	dir       string
+	stop      func()
+	done      chan kit.Nil
+
+	lock      *sync.Mutex
+	downloads []*Download
+	waiters   []chan *Download
+}
+
+// Download of a file
+type Download struct {
+	// GUID of the download
+	GUID string
+	// URL of the resource
+	URL string
+	// SuggestedFilename by the browser, the actual file on disk is named by the GUID
+	SuggestedFilename string
+	// FrameID of the frame that caused the download
+	FrameID proto.PageFrameID
+
+	manager *DownloadManager
+	lock    *sync.Mutex
+	path    string
+	state   proto.PageDownloadProgressState
+	total   int64
+	receive int64
+	done    chan kit.Nil
+}
+
+// DownloadsE starts to manage the downloads of the browser context, the files will be saved to the dir.
+// The download behavior will be reset to default when DownloadManager.Close is called.
+func (b *Browser) DownloadsE(dir string) (*DownloadManager, error) {
+	return newDownloadManager(b, "", b, dir)
+}
+
+// DownloadsE is similar to Browser.DownloadsE, but only the downloads caused by the page will be tracked.
+// The download behavior is shared by all the pages in the same browser context.
+func (p *Page) DownloadsE(dir string) (*DownloadManager, error) {
+	return newDownloadManager(p.browser, p.sessionID(), p, dir)
+}
+
+func newDownloadManager(b *Browser, sessionID proto.TargetSessionID, caller proto.Caller, dir string) (*DownloadManager, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, _, _ := caller.CallContext()
+	ctx, cancel := context.WithCancel(ctx)
+
+	m := &DownloadManager{
+		browser:   b,
+		sessionID: sessionID,
+		dir:       dir,
+		stop:      cancel,
+		done:      make(chan kit.Nil),
+		lock:      &sync.Mutex{},
+		downloads: []*Download{},
+	}
+
+	s := b.event.Subscribe(ctx)
+
+	err = proto.BrowserSetDownloadBehavior{
+		Behavior:         proto.BrowserSetDownloadBehaviorBehaviorAllowAndName,
+		BrowserContextID: b.BrowserContextID,
+		DownloadPath:     dir,
+	}.Call(b)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go func() {
+		defer close(m.done)
+		for msg := range s {
+			m.handle(msg.(*cdp.Event))
+		}
+	}()
+
+	return m, nil
+}
+
+// Dir where the files are saved to
+func (m *DownloadManager) Dir() string {
+	return m.dir
+}
+
+// List the downloads in the order they begin
+func (m *DownloadManager) List() []*Download {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]*Download{}, m.downloads...)
+}
+
+// WaitE returns a function to wait for the next download that begins after WaitE is called
+func (m *DownloadManager) WaitE(ctx context.Context) func() (*Download, error) {
+	ch := make(chan *Download, 1)
+
+	m.lock.Lock()
+	m.waiters = append(m.waiters, ch)
+	m.lock.Unlock()
+
+	return func() (*Download, error) {
+		select {
+		case d := <-ch:
+			return d, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-m.done:
+			return nil, context.Canceled
+		}
+	}
+}
+
+// CloseE stops tracking the downloads and resets the download behavior of the browser context
+func (m *DownloadManager) CloseE() error {
+	m.stop()
+	<-m.done
+
+	return proto.BrowserSetDownloadBehavior{
+		Behavior:         proto.BrowserSetDownloadBehaviorBehaviorDefault,
+		BrowserContextID: m.browser.BrowserContextID,
+	}.Call(m.browser)
+}
+
+func (m *DownloadManager) handle(e *cdp.Event) {
+	if m.sessionID != "" && e.SessionID != string(m.browser.currentSession(m.sessionID)) {
+		return
+	}
+
+	switch e.Method {
+	case (proto.PageDownloadWillBegin{}).MethodName():
+		var evt proto.PageDownloadWillBegin
+		if !Event(e, &evt) {
+			return
+		}
+
+		d := &Download{
+			GUID:              evt.GUID,
+			URL:               evt.URL,
+			SuggestedFilename: evt.SuggestedFilename,
+			FrameID:           evt.FrameID,
+			manager:           m,
+			lock:              &sync.Mutex{},
+			path:              filepath.Join(m.dir, evt.GUID),
+			state:             proto.PageDownloadProgressStateInProgress,
+			done:              make(chan kit.Nil),
+		}
+
+		m.lock.Lock()
+		m.downloads = append(m.downloads, d)
+		waiters := m.waiters
+		m.waiters = nil
+		m.lock.Unlock()
+
+		for _, ch := range waiters {
+			ch <- d
+		}
+
+	case (proto.PageDownloadProgress{}).MethodName():
+		var evt proto.PageDownloadProgress
+		if !Event(e, &evt) {
+			return
+		}
+
+		for _, d := range m.List() {
+			if d.GUID == evt.GUID {
+				d.update(&evt)
+			}
+		}
+	}
+}
+
+// State of the download
+func (d *Download) State() proto.PageDownloadProgressState {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.state
+}
+
+// Progress returns the received bytes and the total expected bytes, the total is 0 if it's unknown
+func (d *Download) Progress() (received, total int64) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.receive, d.total
+}
+
+// Path of the file on disk
+func (d *Download) Path() string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.path
+}
+
+// WaitE waits until the download is completed or canceled, returns ErrDownloadCanceled if it's canceled
+func (d *Download) WaitE(ctx context.Context) error {
+	select {
+	case <-d.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if d.State() == proto.PageDownloadProgressStateCanceled {
+		return ErrDownloadCanceled
+	}
+	return nil
+}
+
+// CancelE the download, it requires the browser to support "Browser.cancelDownload".
+func (d *Download) CancelE() error {
+	params := map[string]interface{}{"guid": d.GUID}
+	if d.manager.browser.BrowserContextID != "" {
+		params["browserContextId"] = d.manager.browser.BrowserContextID
+	}
+	return proto.Call("Browser.cancelDownload", params, nil, d.manager.browser)
+}
+
+// SaveAsE moves the completed download to the path, the Path will be updated
+func (d *Download) SaveAsE(path string) error {
+	err := d.WaitE(d.manager.browser.ctx)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	err = os.Rename(d.path, path)
+	if err != nil {
+		return err
+	}
+	d.path = path
+	return nil
+}
+
+func (d *Download) update(e *proto.PageDownloadProgress) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.state != proto.PageDownloadProgressStateInProgress {
+		return
+	}
+
+	d.receive = int64(e.ReceivedBytes)
+	d.total = int64(e.TotalBytes)
+	d.state = e.State
+
+	if d.state != proto.PageDownloadProgressStateInProgress {
+		close(d.done)
+	}
+}
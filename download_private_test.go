@@ -0,0 +1,63 @@
+package rod
+
+import (
+	"path/filepath"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+	"github.com/ysmood/kit"
+)
+
+func (s *S) TestDownloads() {
+	fake := newFake()
+	b := s.connectFake(fake)
+
+	dir := filepath.Join("tmp", kit.RandString(8))
+	defer func() { _ = kit.Remove(dir) }()
+
+	m := b.Downloads(dir)
+	call := fake.Calls("Browser.setDownloadBehavior")[0].JSONParams()
+	s.Equal("allowAndName", call.Get("behavior").String())
+	s.Equal(m.Dir(), call.Get("downloadPath").String())
+	s.True(filepath.IsAbs(m.Dir()))
+
+	wait := m.Wait()
+	fake.Emit("", "Page.downloadWillBegin", &proto.PageDownloadWillBegin{
+		GUID: "a", URL: "http://a.com/file", SuggestedFilename: "file.txt",
+	})
+	d := wait()
+	s.Equal("file.txt", d.SuggestedFilename)
+	s.Equal(filepath.Join(m.Dir(), "a"), d.Path())
+
+	fake.Emit("", "Page.downloadProgress", &proto.PageDownloadProgress{
+		GUID: "a", ReceivedBytes: 1, TotalBytes: 2, State: proto.PageDownloadProgressStateInProgress,
+	})
+	s.waitUntil(func() bool {
+		received, _ := d.Progress()
+		return received == 1
+	})
+
+	utils.E(kit.OutputFile(d.Path(), "ok", nil))
+	fake.Emit("", "Page.downloadProgress", &proto.PageDownloadProgress{
+		GUID: "a", ReceivedBytes: 2, TotalBytes: 2, State: proto.PageDownloadProgressStateCompleted,
+	})
+	d.Wait()
+	to := filepath.Join(dir, "saved", "file.txt")
+	d.SaveAs(to)
+	s.Equal(to, d.Path())
+	content, err := kit.ReadString(to)
+	s.Nil(err)
+	s.Equal("ok", content)
+
+	wait = m.Wait()
+	fake.Emit("", "Page.downloadWillBegin", &proto.PageDownloadWillBegin{GUID: "b"})
+	d = wait()
+	d.Cancel()
+	s.Equal("b", fake.Calls("Browser.cancelDownload")[0].JSONParams().Get("guid").String())
+	fake.Emit("", "Page.downloadProgress", &proto.PageDownloadProgress{GUID: "b", State: proto.PageDownloadProgressStateCanceled})
+	s.Equal(ErrDownloadCanceled, d.WaitE(b.GetContext()))
+	s.Len(m.List(), 2)
+
+	m.Close()
+	s.Equal("default", fake.Calls("Browser.setDownloadBehavior")[1].JSONParams().Get("behavior").String())
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/tidwall/gjson"
@@ -93,12 +94,13 @@ func (el *Element) ClickE(button proto.InputMouseButton) error {
 
 // ClickableE checks if the element is behind another element, such as when invisible or covered by a modal.
 func (el *Element) ClickableE() (bool, error) {
-	box, err := el.BoxE()
+	// the point is relative to the frame that owns the session of the element
+	box, err := el.box()
 	if err != nil {
 		return false, err
 	}
 
-	scroll, err := el.page.Root().EvalE(true, "", `{ x: window.scrollX, y: window.scrollY }`, nil)
+	scroll, err := el.page.sessionRoot().EvalE(true, "", `{ x: window.scrollX, y: window.scrollY }`, nil)
 	if err != nil {
 		return false, err
 	}
@@ -125,6 +127,46 @@ func (el *Element) ClickableE() (bool, error) {
 
 // BoxE returns the size of an element and its position relative to the main frame.
 func (el *Element) BoxE() (*proto.DOMRect, error) {
+	box, err := el.box()
+	if err != nil {
+		return nil, err
+	}
+
+	// the box from an out-of-process iframe is relative to the document of the iframe
+	if f := el.page.sessionRoot(); f.IsIframe() {
+		x, y, err := f.element.contentOffset()
+		if err != nil {
+			return nil, err
+		}
+		box.X += x
+		box.Y += y
+	}
+
+	return box, nil
+}
+
+// contentOffset returns the position of the content box of the iframe element relative to the main frame,
+// the document of the iframe starts there, the border and padding of the iframe are excluded.
+func (el *Element) contentOffset() (float64, float64, error) {
+	res, err := proto.DOMGetBoxModel{ObjectID: el.ObjectID}.Call(el)
+	if err != nil {
+		return 0, 0, err
+	}
+	x, y := res.Model.Content[0], res.Model.Content[1]
+
+	if f := el.page.sessionRoot(); f.IsIframe() {
+		ox, oy, err := f.element.contentOffset()
+		if err != nil {
+			return 0, 0, err
+		}
+		x += ox
+		y += oy
+	}
+
+	return x, y, nil
+}
+
+func (el *Element) box() (*proto.DOMRect, error) {
 	res, err := proto.DOMGetBoxModel{ObjectID: el.ObjectID}.Call(el)
 	if err != nil {
 		return nil, err
@@ -317,13 +359,16 @@ func (el *Element) ShadowRootE() (*Element, error) {
 	return el.page.ElementFromObject(shadowNode.Object.ObjectID), nil
 }
 
-// Frame creates a page instance that represents the iframe
+// Frame creates a page instance that represents the iframe.
+// If the iframe is out-of-process, such as a cross-origin one, the page will switch to the session of
+// the iframe when its execution context is first required.
 func (el *Element) Frame() *Page {
-	newPage := *el.page
+	newPage := el.page.clone()
+	newPage.sessionLock = &sync.RWMutex{}
 	newPage.element = el
 	newPage.jsHelperObjectID = ""
 	newPage.windowObjectID = ""
-	return &newPage
+	return newPage
 }
 
 // ContainsElementE check if the target is equal or inside the element.
@@ -524,7 +569,7 @@ func (el *Element) ReleaseE() error {
 
 // CallContext parameters for proto
 func (el *Element) CallContext() (context.Context, proto.Client, string) {
-	return el.ctx, el.page.browser, string(el.page.SessionID)
+	return el.ctx, el.page.browser, string(el.page.sessionID())
 }
 
 // EvalE doc is similar to the method Eval
@@ -552,6 +597,15 @@ func (el *Element) ensureParentPage(nodeID proto.DOMNodeID, objID proto.RuntimeR
 		for _, f := range list {
 			p := f.Frame()
 
+			// resolve the session of the iframe first
+			_, err := p.getExecutionID(false)
+			if err != nil {
+				return err
+			}
+			if p.isOutOfProcess() {
+				continue // the node id is only meaningful in the session of the element
+			}
+
 			objID, err := p.resolveNode(nodeID)
 			if err != nil {
 				return err
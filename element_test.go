@@ -8,6 +8,7 @@ import (
 	"image/color"
 	"image/png"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
@@ -127,6 +128,38 @@ func (s *S) TestIframes() {
 	})
 }
 
+func (s *S) TestIframeOutOfProcess() {
+	frameURL, frameEngine, closeFrame := serve()
+	defer closeFrame()
+	frameEngine.GET("/", ginHTML(`<button onclick="this.setAttribute('a', 'ok')">ok</button>`))
+
+	// use a different site from the page, so that the browser will put the iframe into another process
+	frameURL = strings.Replace(frameURL, "127.0.0.1", "localhost", 1)
+
+	url, engine, close := serve()
+	defer close()
+	engine.GET("/", ginHTML(`<iframe src="`+frameURL+`"></iframe>`))
+
+	page := s.browser.Page(url).WaitLoad()
+	defer page.Close()
+
+	frame := page.Element("iframe").Frame()
+	frame.Element("button").Click()
+	s.True(frame.Has("[a=ok]"))
+	s.Equal(page, frame.Root())
+
+	frames := page.Frames()
+	s.Len(frames, 2)
+	s.EqualValues(frames[0].ID, frames[1].ParentID)
+	s.Equal(frameURL+"/", frames[1].URL)
+
+	wait := page.EachFrameEvent(func(_ *proto.PageFrameAttached, e *proto.PageFrameNavigated, _ *proto.PageFrameDetached) bool {
+		return e != nil && e.Frame.ParentID != ""
+	})
+	page.Element("iframe").Eval(`() => this.src += "?a"`)
+	wait()
+}
+
 func (s *S) TestContains() {
 	p := s.page.Navigate(srcFile("fixtures/click.html"))
 	a := p.Element("button")
@@ -462,7 +495,9 @@ func (s *S) TestUseReleasedElement() {
 
 	btn = p.Element("button")
 	utils.E(proto.RuntimeReleaseObject{ObjectID: btn.ObjectID}.Call(p))
-	s.EqualError(btn.ClickE("left"), "{\"code\":-32000,\"message\":\"Could not find object with given id\",\"data\":\"\"}")
+	err := btn.ClickE("left")
+	s.EqualError(err, "{\"code\":-32000,\"message\":\"Could not find object with given id\",\"data\":\"\"}")
+	s.True(errors.Is(err, rod.ErrObjectNotFound))
 }
 
 func (s *S) TestElementMultipleTimes() {
@@ -2,6 +2,9 @@ package rod
 
 import (
 	"errors"
+	"fmt"
+
+	"github.com/go-rod/rod/lib/cdp"
 )
 
 var (
@@ -21,8 +24,39 @@ var (
 	ErrEval = errors.New("[rod] eval error")
 	// ErrNavigation error
 	ErrNavigation = errors.New("[rod] navigation failed")
+	// ErrNavigationAborted error, it's also an ErrNavigation
+	ErrNavigationAborted = fmt.Errorf("%w: aborted", ErrNavigation)
 	// ErrNotClickable error
 	ErrNotClickable = errors.New("[rod] element is not clickable")
+	// ErrPoolClosed error
+	ErrPoolClosed = errors.New("[rod] pool is closed")
+	// ErrNotFromPool error
+	ErrNotFromPool = errors.New("[rod] the page doesn't belong to the pool")
+	// ErrMockExpectation error
+	ErrMockExpectation = errors.New("[rod] mock expectation not met")
+	// ErrBodyConsumed error, the handler reads the real response body without setting a new one
+	ErrBodyConsumed = errors.New("[rod] the response body is consumed without a new one")
+	// ErrDownloadCanceled error
+	ErrDownloadCanceled = errors.New("[rod] download canceled")
+	// ErrNotActionable error, such as the element is invisible or disabled
+	ErrNotActionable = errors.New("[rod] element is not actionable")
+	// ErrAXNodeNotFound error
+	ErrAXNodeNotFound = errors.New("[rod] cannot find accessibility node")
+	// ErrFrameNotFound error
+	ErrFrameNotFound = errors.New("[rod] cannot find frame")
+)
+
+// The classification of the errors from the browser, use them with errors.Is to tell
+// transient failures from bugs, such as retry the action when the error is ErrContextDestroyed.
+var (
+	// ErrTargetClosed error
+	ErrTargetClosed = cdp.ErrTargetClosed
+	// ErrSessionNotFound error
+	ErrSessionNotFound = cdp.ErrSessionNotFound
+	// ErrObjectNotFound error, such as a stale node after navigation
+	ErrObjectNotFound = cdp.ErrObjectNotFound
+	// ErrContextDestroyed error
+	ErrContextDestroyed = cdp.ErrContextDestroyed
 )
 
 // Error ...
@@ -0,0 +1,146 @@
+package rod
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// EvalOptions for the EvaluateE
+type EvalOptions struct {
+	// This is the `this` of the js function, if it's nil the window object will be used
+	This *Element
+
+	// JS is a function definition or an expression
+	JS string
+
+	// JSArgs are the Go values that will be passed to the js function, they are encoded via encoding/json.
+	// The *Element in them, even if it's nested in maps, slices or structs, will be the DOM node in the js.
+	JSArgs []interface{}
+
+	// AwaitPromise waits for the promise returned by the js to settle, and uses the settled value as the result
+	AwaitPromise bool
+}
+
+// Eval creates the EvalOptions for the js with the args, it awaits the promise returned by the js
+func Eval(js string, args ...interface{}) *EvalOptions {
+	return &EvalOptions{
+		JS:           js,
+		JSArgs:       args,
+		AwaitPromise: true,
+	}
+}
+
+// EvaluateE evaluates the opts and decodes the result into the result via json.Unmarshal,
+// the result will be untouched if it's nil or the js returns undefined.
+// If the js throws, the error will be a *JSException that has the stack trace of the exception.
+func (p *Page) EvaluateE(opts *EvalOptions, result interface{}) error {
+	var thisID proto.RuntimeRemoteObjectID
+	if opts.This != nil {
+		thisID = opts.This.ObjectID
+	}
+
+	args, ids, err := evalArgs(opts.JSArgs)
+	if err != nil {
+		return err
+	}
+
+	jsArgs := Array{args}
+	for _, id := range ids {
+		jsArgs = append(jsArgs, id)
+	}
+
+	res, err := p.callFunction(true, opts.AwaitPromise, thisID, sprintEvalFn(opts.JS), jsArgs)
+	if err != nil {
+		return err
+	}
+
+	if res.ExceptionDetails != nil {
+		return newJSException(res.ExceptionDetails)
+	}
+
+	if result == nil || res.Result.Value.Raw == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(res.Result.Value.Raw), result)
+}
+
+// EvaluateE is similar to Page.EvaluateE, but the `this` of the js will be the element
+func (el *Element) EvaluateE(opts *EvalOptions, result interface{}) error {
+	o := *opts
+	o.This = el
+	return el.page.Context(el.ctx, el.ctxCancel).EvaluateE(&o, result)
+}
+
+// the key of the placeholder object that stands for the element in the transferred args
+const evalElementKey = "rodElement"
+
+// sprintEvalFn wraps the js, it replaces the placeholders in the args with the elements that
+// are passed as the rest arguments, then applies the args to the js.
+func sprintEvalFn(js string) string {
+	call := js
+	if detectJSFunction(js) {
+		call = fmt.Sprintf(`(%s).apply(this, args)`, js)
+	}
+
+	return fmt.Sprintf(`function(args, ...elements) {
+	const revive = (v) => {
+		if (Array.isArray(v)) return v.map(revive)
+		if (v === null || typeof v !== 'object') return v
+		const keys = Object.keys(v)
+		if (keys.length === 1 && keys[0] === '%s') return elements[v.%s]
+		const obj = {}
+		for (const k of keys) obj[k] = revive(v[k])
+		return obj
+	}
+	args = revive(args)
+	return %s
+}`, evalElementKey, evalElementKey, call)
+}
+
+// MarshalJSON encodes the element as a placeholder object, such as {"rodElement":"<object id>"}.
+// The EvaluateE uses it to pass the elements in the args to the js.
+func (el *Element) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]proto.RuntimeRemoteObjectID{evalElementKey: el.ObjectID})
+}
+
+// evalArgs encodes the args via encoding/json, the placeholders of the elements in them are replaced
+// with the indexes of the elements, the object ids of the elements are returned in the same order.
+func evalArgs(args []interface{}) (interface{}, []proto.RuntimeRemoteObjectID, error) {
+	b, err := json.Marshal(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var val interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	err = d.Decode(&val)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := []proto.RuntimeRemoteObjectID{}
+	var walk func(interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case []interface{}:
+			for i, item := range v {
+				v[i] = walk(item)
+			}
+		case map[string]interface{}:
+			if id, ok := v[evalElementKey].(string); ok && len(v) == 1 {
+				ids = append(ids, proto.RuntimeRemoteObjectID(id))
+				return map[string]int{evalElementKey: len(ids) - 1}
+			}
+			for k, item := range v {
+				v[k] = walk(item)
+			}
+		}
+		return v
+	}
+
+	return walk(val), ids, nil
+}
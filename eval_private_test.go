@@ -0,0 +1,109 @@
+package rod
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/go-rod/rod/lib/assets"
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func (s *S) TestEvaluate() {
+	fake := newFake().
+		Handle("Runtime.callFunctionOn", func(call *cdptest.Call) (interface{}, error) {
+			params := call.JSONParams()
+			if params.Get("functionDeclaration").String() == assets.Helper {
+				return &proto.RuntimeCallFunctionOnResult{Result: &proto.RuntimeRemoteObject{ObjectID: "helper", Value: proto.NewJSON(nil)}}, nil
+			}
+			if params.Get("arguments.0.value.0").String() == "throw" {
+				return &proto.RuntimeCallFunctionOnResult{
+					Result: &proto.RuntimeRemoteObject{Value: proto.NewJSON(nil)},
+					ExceptionDetails: &proto.RuntimeExceptionDetails{
+						Text: "Uncaught", LineNumber: 1,
+						StackTrace: &proto.RuntimeStackTrace{CallFrames: []*proto.RuntimeCallFrame{{FunctionName: "fn"}}},
+						Exception:  &proto.RuntimeRemoteObject{Description: "Error: boom", Value: proto.NewJSON(nil)},
+					},
+				}, nil
+			}
+			return &proto.RuntimeCallFunctionOnResult{Result: &proto.RuntimeRemoteObject{
+				Value: proto.NewJSON(map[string]interface{}{"name": "rod", "list": []int{1, 2}}),
+			}}, nil
+		})
+
+	p := s.newFakePage(fake)
+	el := &Element{ObjectID: "el", page: p, ctx: p.ctx}
+
+	type arg struct {
+		Target *Element `json:"target"`
+		Skip   string   `json:"-"`
+		Label  string
+	}
+
+	var res struct {
+		Name string
+		List []int
+	}
+	p.Evaluate(Eval(`(a, b) => b`, map[string]interface{}{"els": []*Element{el, nil}}, arg{el, "x", "y"}), &res)
+	s.Equal("rod", res.Name)
+	s.Equal([]int{1, 2}, res.List)
+
+	call := fake.Calls("Runtime.callFunctionOn")[1]
+	params := call.JSONParams()
+	s.Equal("window", params.Get("objectId").String())
+	s.True(params.Get("awaitPromise").Bool())
+	s.True(params.Get("returnByValue").Bool())
+	s.Contains(params.Get("functionDeclaration").String(), "((a, b) => b).apply(this, args)")
+	s.JSONEq(`[{"els":[{"rodElement":0},null]},{"target":{"rodElement":1},"Label":"y"}]`,
+		params.Get("arguments.0.value").Raw)
+	s.Equal("el", params.Get("arguments.1.objectId").String())
+	s.Equal("el", params.Get("arguments.2.objectId").String())
+
+	opts := Eval(`1`)
+	opts.AwaitPromise = false
+	el.Evaluate(opts, nil)
+	params = fake.Calls("Runtime.callFunctionOn")[2].JSONParams()
+	s.Equal("el", params.Get("objectId").String())
+	s.False(params.Get("awaitPromise").Bool())
+	s.Contains(params.Get("functionDeclaration").String(), "return 1\n")
+
+	err := p.EvaluateE(Eval(`() => { throw new Error('boom') }`, "throw"), nil)
+	var exp *JSException
+	s.True(errors.As(err, &exp))
+	s.Equal("Uncaught Error: boom", exp.Error())
+	s.Equal("fn", exp.StackTrace.CallFrames[0].FunctionName)
+}
+
+type evalBase struct {
+	ID   int         `json:"id"`
+	Name string      `json:"name,omitempty"`
+	Any  interface{} `json:"any"`
+}
+
+func (s *S) TestEvalArgs() {
+	el := &Element{ObjectID: "el"}
+	args := []interface{}{
+		struct {
+			evalBase
+			El   *Element `json:"el,omitempty"`
+			Skip string   `json:"-"`
+		}{evalBase: evalBase{ID: 1, Any: el}, Skip: "x"},
+		int64(1) << 60,
+		map[string]interface{}{"rodElement": 1, "list": []*Element{nil, el}},
+	}
+
+	val, ids, err := evalArgs(args)
+	s.Nil(err)
+	actual, err := json.Marshal(val)
+	s.Nil(err)
+	s.JSONEq(`[
+		{"id":1,"any":{"rodElement":0}},
+		1152921504606846976,
+		{"rodElement":1,"list":[null,{"rodElement":1}]}
+	]`, string(actual))
+	s.Contains(string(actual), "1152921504606846976") // the precision of the numbers is kept
+	s.Equal([]proto.RuntimeRemoteObjectID{"el", "el"}, ids)
+
+	_, _, err = evalArgs([]interface{}{make(chan int)})
+	s.Error(err)
+}
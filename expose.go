@@ -0,0 +1,129 @@
+package rod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/tidwall/gjson"
+)
+
+// ExposeFuncE exposes the fn to the window object of the page as window[name], it also works for the documents
+// that are loaded later. The js side of it returns a promise, the promise resolves with the JSON of
+// the fn's result, or rejects with an Error that has the message of the fn's error or panic.
+// Each call runs the fn in its own goroutine, call the stop to remove it.
+func (p *Page) ExposeFuncE(name string, fn func(args []gjson.Result) (interface{}, error)) (stop func(), err error) {
+	binding := "rodExpose_" + name
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	s := p.browser.event.Subscribe(ctx)
+	recoverRuntime := p.EnableDomain(&proto.RuntimeEnable{})
+
+	err = proto.RuntimeAddBinding{Name: binding}.Call(p)
+	if err != nil {
+		cancel()
+		recoverRuntime()
+		return nil, err
+	}
+
+	scriptID, err := p.EvalOnNewDocumentE(fmt.Sprintf(`(%s)(%s, %s)`, jsExposeFunc, quoteJS(name), quoteJS(binding)))
+	if err != nil {
+		cancel()
+		recoverRuntime()
+		return nil, err
+	}
+
+	stop = func() {
+		cancel()
+		recoverRuntime()
+		_ = proto.RuntimeRemoveBinding{Name: binding}.Call(p)
+		_ = proto.PageRemoveScriptToEvaluateOnNewDocument{Identifier: scriptID}.Call(p)
+	}
+
+	// the current document
+	_, err = p.EvalE(true, "", jsExposeFunc, Array{name, binding})
+	if err != nil {
+		stop()
+		return nil, err
+	}
+
+	go func() {
+		for msg := range s {
+			e := msg.(*cdp.Event)
+			if e.SessionID != string(p.browser.currentSession(p.sessionID())) {
+				continue
+			}
+
+			var evt proto.RuntimeBindingCalled
+			if Event(e, &evt) && evt.Name == binding {
+				go p.settleExposed(binding, &evt, fn)
+			}
+		}
+	}()
+
+	return stop, nil
+}
+
+// settleExposed calls the fn with the args of the call, and settles the promise of the call with the result of the fn
+func (p *Page) settleExposed(binding string, e *proto.RuntimeBindingCalled, fn func([]gjson.Result) (interface{}, error)) {
+	payload := gjson.Parse(e.Payload)
+
+	errMsg := "null"
+	res, err := callExposed(fn, payload.Get("args").Array())
+	data, marshalErr := json.Marshal(res)
+	if err == nil {
+		err = marshalErr
+	}
+	if err != nil {
+		errMsg = quoteJS(err.Error())
+		data = []byte("null")
+	}
+
+	_, _ = proto.RuntimeEvaluate{
+		Expression: fmt.Sprintf(`window[%s].settle(%d, %s, %s)`, quoteJS(binding), payload.Get("id").Int(), errMsg, data),
+		ContextID:  e.ExecutionContextID,
+	}.Call(p)
+}
+
+// callExposed calls the fn, if the fn panics the panic will be returned as the error, so that
+// the promise of the call will be rejected instead of crashing the process
+func callExposed(fn func([]gjson.Result) (interface{}, error), args []gjson.Result) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return fn(args)
+}
+
+// quoteJS returns the str as a js string literal
+func quoteJS(str string) string {
+	data, _ := json.Marshal(str)
+	return string(data)
+}
+
+// jsExposeFunc creates the window[name] that sends the calls to the binding, the calls are correlated by their ids,
+// the binding.settle will be called by the Go side to settle the promise of a call.
+const jsExposeFunc = `function(name, binding) {
+	const send = window[binding]
+	if (!send || send.settle) return
+
+	const calls = new Map()
+	let id = 0
+
+	send.settle = (id, err, res) => {
+		const call = calls.get(id)
+		if (!call) return
+		calls.delete(id)
+		if (err === null) call.resolve(res)
+		else call.reject(new Error(err))
+	}
+
+	window[name] = (...args) => new Promise((resolve, reject) => {
+		id++
+		calls.set(id, { resolve, reject })
+		send(JSON.stringify({ id, args }))
+	})
+}`
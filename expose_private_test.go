@@ -0,0 +1,72 @@
+package rod
+
+import (
+	"errors"
+
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/tidwall/gjson"
+)
+
+func (s *S) TestExposeFunc() {
+	fake := newFake().
+		Handle("Page.addScriptToEvaluateOnNewDocument", cdptest.Result(&proto.PageAddScriptToEvaluateOnNewDocumentResult{
+			Identifier: "script",
+		}))
+
+	p := s.newFakePage(fake)
+
+	stop := p.ExposeFunc("add", func(args []gjson.Result) (interface{}, error) {
+		if len(args) == 1 {
+			panic("boom")
+		}
+		if len(args) != 2 {
+			return nil, errors.New("two args required")
+		}
+		return args[0].Int() + args[1].Int(), nil
+	})
+
+	s.Equal("rodExpose_add", fake.Calls("Runtime.addBinding")[0].JSONParams().Get("name").String())
+	s.Contains(fake.Calls("Page.addScriptToEvaluateOnNewDocument")[0].JSONParams().Get("source").String(),
+		`("add", "rodExpose_add")`)
+
+	settled := func(contextID int) string {
+		var expression string
+		s.waitUntil(func() bool {
+			for _, call := range fake.Calls("Runtime.evaluate") {
+				if call.JSONParams().Get("contextId").Int() == int64(contextID) {
+					expression = call.JSONParams().Get("expression").String()
+					return true
+				}
+			}
+			return false
+		})
+		return expression
+	}
+
+	fake.Emit("other", "Runtime.bindingCalled", &proto.RuntimeBindingCalled{
+		Name: "rodExpose_add", Payload: `{"id":1,"args":[1,2]}`, ExecutionContextID: 1,
+	})
+	fake.Emit("s", "Runtime.bindingCalled", &proto.RuntimeBindingCalled{
+		Name: "rodExpose_add", Payload: `{"id":2,"args":[1,2]}`, ExecutionContextID: 2,
+	})
+	s.Equal(`window["rodExpose_add"].settle(2, null, 3)`, settled(2))
+
+	fake.Emit("s", "Runtime.bindingCalled", &proto.RuntimeBindingCalled{
+		Name: "rodExpose_add", Payload: `{"id":3,"args":[]}`, ExecutionContextID: 3,
+	})
+	s.Equal(`window["rodExpose_add"].settle(3, "two args required", null)`, settled(3))
+
+	fake.Emit("s", "Runtime.bindingCalled", &proto.RuntimeBindingCalled{
+		Name: "rodExpose_add", Payload: `{"id":4,"args":[1]}`, ExecutionContextID: 4,
+	})
+	s.Equal(`window["rodExpose_add"].settle(4, "boom", null)`, settled(4))
+
+	for _, call := range fake.Calls("Runtime.evaluate") {
+		s.NotEqualValues(1, call.JSONParams().Get("contextId").Int())
+	}
+
+	stop()
+	s.Equal("rodExpose_add", fake.Calls("Runtime.removeBinding")[0].JSONParams().Get("name").String())
+	s.Equal("script", fake.Calls("Page.removeScriptToEvaluateOnNewDocument")[0].JSONParams().Get("identifier").String())
+}
@@ -0,0 +1,359 @@
+package rod
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/goob"
+)
+
+// FrameTreeE returns the frame tree of the page, the out-of-process iframes are merged into it.
+// If the page is an iframe, the tree starts from the iframe.
+func (p *Page) FrameTreeE() (*proto.PageFrameTree, error) {
+	err := p.startFrames()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := proto.PageGetFrameTree{}.Call(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// the trees of the out-of-process iframes, keyed by their frame ids
+	oopifs := map[proto.PageFrameID]*proto.PageFrameTree{}
+	for frameID, sessionID := range p.frameSessionList() {
+		res, err := proto.PageGetFrameTree{}.Call(p.withSession(sessionID))
+		if errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrTargetClosed) {
+			continue // the iframe is detached
+		} else if err != nil {
+			return nil, err
+		}
+		oopifs[frameID] = res.FrameTree
+	}
+
+	tree := mergeFrameTree(res.FrameTree, oopifs)
+
+	if p.IsIframe() && !p.isOutOfProcess() {
+		frameID, err := p.frameID()
+		if err != nil {
+			return nil, err
+		}
+		tree = findFrameTree(tree, frameID)
+		if tree == nil {
+			return nil, newErr(ErrFrameNotFound, frameID)
+		}
+	}
+
+	return tree, nil
+}
+
+// FramesE returns the frames of the FrameTreeE as a list, the parent frames are always before their children
+func (p *Page) FramesE() ([]*proto.PageFrame, error) {
+	tree, err := p.FrameTreeE()
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*proto.PageFrame{}
+	var walk func(*proto.PageFrameTree)
+	walk = func(t *proto.PageFrameTree) {
+		list = append(list, t.Frame)
+		for _, child := range t.ChildFrames {
+			walk(child)
+		}
+	}
+	walk(tree)
+
+	return list, nil
+}
+
+// EachFrameEvent of the frames of the page, including the ones of the out-of-process iframes.
+// Only one argument of the fn will be non-null, if the fn returns true the event loop will stop.
+func (p *Page) EachFrameEvent(fn func(
+	attached *proto.PageFrameAttached,
+	navigated *proto.PageFrameNavigated,
+	detached *proto.PageFrameDetached,
+) (stop bool)) (wait func()) {
+	// if the tracking fails to start, the events of the same-process frames are still reported
+	_ = p.startFrames()
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	s := p.browser.event.Subscribe(ctx)
+
+	return func() {
+		defer func() {
+			cancel()
+			s = nil
+		}()
+
+		if s == nil {
+			panic("can't use wait function twice")
+		}
+
+		goob.Each(s, func(e *cdp.Event) bool {
+			if !p.ownsSession(proto.TargetSessionID(e.SessionID)) {
+				return false
+			}
+
+			var attached *proto.PageFrameAttached
+			var navigated *proto.PageFrameNavigated
+			var detached *proto.PageFrameDetached
+
+			switch e.Method {
+			case (proto.PageFrameAttached{}).MethodName():
+				attached = &proto.PageFrameAttached{}
+				if !Event(e, attached) {
+					return false
+				}
+			case (proto.PageFrameNavigated{}).MethodName():
+				navigated = &proto.PageFrameNavigated{}
+				if !Event(e, navigated) {
+					return false
+				}
+			case (proto.PageFrameDetached{}).MethodName():
+				detached = &proto.PageFrameDetached{}
+				if !Event(e, detached) {
+					return false
+				}
+			default:
+				return false
+			}
+
+			return fn(attached, navigated, detached)
+		})
+	}
+}
+
+// targetTypeIframe is the type of the out-of-process iframe targets, the enum of the schema doesn't include it
+const targetTypeIframe proto.TargetTargetInfoType = "iframe"
+
+// pageFrames tracks the out-of-process iframes of a page, it's shared by the page and its iframes
+type pageFrames struct {
+	ctx  context.Context // the context of the page, the tracking stops when it's done
+	lock *sync.Mutex
+
+	started  bool
+	sessions *sync.Map // frame id to the session id of the out-of-process iframes
+}
+
+func newPageFrames(ctx context.Context) *pageFrames {
+	return &pageFrames{
+		ctx:      ctx,
+		lock:     &sync.Mutex{},
+		sessions: &sync.Map{},
+	}
+}
+
+// startFrames auto-attaches to the out-of-process iframes of the page, so that they can be
+// used the same way as the same-process ones. The tracking starts on the first call of the
+// frame related methods, the pages that never use them won't pay for it.
+func (p *Page) startFrames() error {
+	f := p.frames
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.started {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(f.ctx)
+	root := p.Root().Context(ctx, cancel)
+	s := p.browser.event.Subscribe(ctx)
+
+	err := proto.TargetSetAutoAttach{AutoAttach: true, Flatten: true}.Call(root)
+	if err != nil {
+		cancel()
+		return err
+	}
+	f.started = true
+
+	go func() {
+		defer cancel()
+
+		for msg := range s {
+			e := msg.(*cdp.Event)
+			if !root.ownsSession(proto.TargetSessionID(e.SessionID)) {
+				continue
+			}
+
+			switch e.Method {
+			case (proto.TargetAttachedToTarget{}).MethodName():
+				var evt proto.TargetAttachedToTarget
+				if Event(e, &evt) && evt.TargetInfo.Type == targetTypeIframe {
+					root.attachFrame(proto.PageFrameID(evt.TargetInfo.TargetID), evt.SessionID)
+				}
+
+			case (proto.TargetDetachedFromTarget{}).MethodName():
+				var evt proto.TargetDetachedFromTarget
+				if !Event(e, &evt) {
+					continue
+				}
+				for frameID, sessionID := range root.frameSessionList() {
+					if sessionID == evt.SessionID {
+						root.frames.sessions.Delete(frameID)
+					}
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// attachFrame enables the domains of the out-of-process iframe session and auto-attaches
+// to its own out-of-process iframes.
+func (p *Page) attachFrame(frameID proto.PageFrameID, sessionID proto.TargetSessionID) {
+	p.frames.sessions.Store(frameID, sessionID)
+
+	f := p.withSession(sessionID)
+	f.EnableDomain(&proto.PageEnable{})
+	f.EnableDomain(&proto.DOMEnable{})
+	_ = proto.TargetSetAutoAttach{AutoAttach: true, Flatten: true}.Call(f)
+	if p.console != nil && p.console.isStarted() {
+		_ = f.enableConsole()
+	}
+}
+
+// frameSession returns the session of the out-of-process iframe, it returns empty if the
+// frame shares the session with its parent.
+func (p *Page) frameSession(frameID proto.PageFrameID) (proto.TargetSessionID, error) {
+	if id, has := p.frames.sessions.Load(frameID); has {
+		return id.(proto.TargetSessionID), nil
+	}
+
+	// the auto-attach event may not arrive yet
+	b := p.browser.Context(p.ctx, p.ctxCancel)
+	info, err := proto.TargetGetTargetInfo{TargetID: proto.TargetTargetID(frameID)}.Call(b)
+	if errors.Is(err, ErrTargetClosed) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if info.TargetInfo.Type != targetTypeIframe {
+		return "", nil
+	}
+
+	res, err := proto.TargetAttachToTarget{TargetID: info.TargetInfo.TargetID, Flatten: true}.Call(b)
+	if err != nil {
+		return "", err
+	}
+
+	if id, has := p.frames.sessions.Load(frameID); has {
+		return id.(proto.TargetSessionID), nil
+	}
+	p.attachFrame(frameID, res.SessionID)
+	return res.SessionID, nil
+}
+
+func (p *Page) frameSessionList() map[proto.PageFrameID]proto.TargetSessionID {
+	list := map[proto.PageFrameID]proto.TargetSessionID{}
+	p.frames.sessions.Range(func(k, v interface{}) bool {
+		list[k.(proto.PageFrameID)] = v.(proto.TargetSessionID)
+		return true
+	})
+	return list
+}
+
+// ownsSession checks if the session belongs to the page or one of its out-of-process iframes
+func (p *Page) ownsSession(sessionID proto.TargetSessionID) bool {
+	if sessionID == p.browser.currentSession(p.Root().sessionID()) {
+		return true
+	}
+	for _, id := range p.frameSessionList() {
+		if id == sessionID {
+			return true
+		}
+	}
+	return false
+}
+
+// useFrameSession switches the iframe page to the session of the out-of-process iframe
+func (p *Page) useFrameSession(frameID proto.PageFrameID, sessionID proto.TargetSessionID) {
+	p.sessionLock.Lock()
+	defer p.sessionLock.Unlock()
+	p.TargetID = proto.TargetTargetID(frameID)
+	p.SessionID = sessionID
+}
+
+// sessionID of the page, the iframe page may switch it concurrently via the useFrameSession
+func (p *Page) sessionID() proto.TargetSessionID {
+	p.sessionLock.RLock()
+	defer p.sessionLock.RUnlock()
+	return p.SessionID
+}
+
+// targetID of the page, the iframe page may switch it concurrently via the useFrameSession
+func (p *Page) targetID() proto.TargetTargetID {
+	p.sessionLock.RLock()
+	defer p.sessionLock.RUnlock()
+	return p.TargetID
+}
+
+// clone returns a copy of the page, the copy shares the states of the page except the session fields
+func (p *Page) clone() *Page {
+	p.sessionLock.RLock()
+	defer p.sessionLock.RUnlock()
+	f := *p
+	return &f
+}
+
+// isOutOfProcess tells if the page is an iframe that has its own session
+func (p *Page) isOutOfProcess() bool {
+	return p.IsIframe() && p.sessionID() != p.element.page.sessionID()
+}
+
+// sessionRoot returns the top most frame that shares the same session with the page
+func (p *Page) sessionRoot() *Page {
+	f := p
+	for f.IsIframe() && !f.isOutOfProcess() {
+		f = f.element.page
+	}
+	return f
+}
+
+func (p *Page) withSession(sessionID proto.TargetSessionID) *Page {
+	f := p.clone()
+	f.SessionID = sessionID
+	return f
+}
+
+// mergeFrameTree replaces the frames that have their own sessions with the trees from the sessions.
+// If the tree of the parent session doesn't contain the iframe, it will be appended to its parent frame.
+func mergeFrameTree(tree *proto.PageFrameTree, oopifs map[proto.PageFrameID]*proto.PageFrameTree) *proto.PageFrameTree {
+	if sub, has := oopifs[tree.Frame.ID]; has {
+		delete(oopifs, tree.Frame.ID)
+		tree = sub
+	}
+
+	children := []*proto.PageFrameTree{}
+	has := map[proto.PageFrameID]bool{}
+	for _, child := range tree.ChildFrames {
+		has[child.Frame.ID] = true
+	}
+	for id, sub := range oopifs {
+		if !has[id] && proto.PageFrameID(sub.Frame.ParentID) == tree.Frame.ID {
+			tree.ChildFrames = append(tree.ChildFrames, sub)
+		}
+	}
+	for _, child := range tree.ChildFrames {
+		children = append(children, mergeFrameTree(child, oopifs))
+	}
+
+	return &proto.PageFrameTree{Frame: tree.Frame, ChildFrames: children}
+}
+
+func findFrameTree(tree *proto.PageFrameTree, frameID proto.PageFrameID) *proto.PageFrameTree {
+	if tree.Frame.ID == frameID {
+		return tree
+	}
+	for _, child := range tree.ChildFrames {
+		if t := findFrameTree(child, frameID); t != nil {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package rod
+
+import (
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func (s *S) TestFrames() {
+	fake := newFake().
+		Handle("Target.createTarget", cdptest.Result(&proto.TargetCreateTargetResult{TargetID: "main"})).
+		Handle("Target.attachToTarget", func(call *cdptest.Call) (interface{}, error) {
+			if call.JSONParams().Get("targetId").String() == "late" {
+				return &proto.TargetAttachToTargetResult{SessionID: "l"}, nil
+			}
+			return &proto.TargetAttachToTargetResult{SessionID: "s"}, nil
+		}).
+		Handle("Target.getTargetInfo", func(call *cdptest.Call) (interface{}, error) {
+			if call.JSONParams().Get("targetId").String() == "late" {
+				return &proto.TargetGetTargetInfoResult{TargetInfo: &proto.TargetTargetInfo{
+					TargetID: "late", Type: targetTypeIframe,
+				}}, nil
+			}
+			return nil, &cdp.Error{Code: -32602, Message: "No target with given id found"}
+		}).
+		Handle("Page.getFrameTree", func(call *cdptest.Call) (interface{}, error) {
+			if call.SessionID == "f" {
+				return &proto.PageGetFrameTreeResult{FrameTree: &proto.PageFrameTree{
+					Frame: &proto.PageFrame{ID: "oopif", ParentID: "main", URL: "http://b.com"},
+				}}, nil
+			}
+			return &proto.PageGetFrameTreeResult{FrameTree: &proto.PageFrameTree{
+				Frame:       &proto.PageFrame{ID: "main"},
+				ChildFrames: []*proto.PageFrameTree{{Frame: &proto.PageFrame{ID: "same", ParentID: "main"}}},
+			}}, nil
+		}).
+		Handle("DOM.describeNode", func(call *cdptest.Call) (interface{}, error) {
+			id := call.JSONParams().Get("objectId").String()
+			return &proto.DOMDescribeNodeResult{Node: &proto.DOMNode{FrameID: proto.PageFrameID(id)}}, nil
+		}).
+		Handle("Page.createIsolatedWorld", func(call *cdptest.Call) (interface{}, error) {
+			if call.JSONParams().Get("frameId").String() == "same" {
+				return &proto.PageCreateIsolatedWorldResult{ExecutionContextID: 1}, nil
+			}
+			return nil, &cdp.Error{Code: -32000, Message: "No frame for given id found"}
+		}).
+		Handle("DOM.getBoxModel", func(call *cdptest.Call) (interface{}, error) {
+			x := 1.0
+			if call.SessionID == "s" {
+				x = 10
+			}
+			return &proto.DOMGetBoxModelResult{Model: &proto.DOMBoxModel{
+				Content: proto.DOMQuad{x, x, x + 2, x, x + 2, x + 2, x, x + 2},
+				Border:  proto.DOMQuad{x - 5, x - 5, x + 7, x - 5, x + 7, x + 7, x - 5, x + 7},
+			}}, nil
+		})
+
+	p := s.newFakePage(fake)
+
+	// the tracking starts on the first call of the frame related methods
+	s.Equal(0, fake.Called("Target.setAutoAttach"))
+	s.Len(p.Frames(), 2)
+	s.True(fake.Calls("Target.setAutoAttach")[0].JSONParams().Get("autoAttach").Bool())
+	p.Frames()
+	s.Equal(1, fake.Called("Target.setAutoAttach"))
+
+	fake.Emit("other", "Target.attachedToTarget", &proto.TargetAttachedToTarget{
+		SessionID: "x", TargetInfo: &proto.TargetTargetInfo{TargetID: "x", Type: targetTypeIframe},
+	})
+	fake.Emit("s", "Target.attachedToTarget", &proto.TargetAttachedToTarget{
+		SessionID: "f", TargetInfo: &proto.TargetTargetInfo{TargetID: "oopif", Type: targetTypeIframe},
+	})
+	s.waitUntil(func() bool {
+		for _, c := range fake.Calls("Target.setAutoAttach") {
+			if c.SessionID == "f" {
+				return true
+			}
+		}
+		return false
+	})
+	s.Equal(map[proto.PageFrameID]proto.TargetSessionID{"oopif": "f"}, p.frameSessionList())
+
+	ids := []proto.PageFrameID{}
+	for _, f := range p.Frames() {
+		ids = append(ids, f.ID)
+	}
+	s.Equal([]proto.PageFrameID{"main", "same", "oopif"}, ids)
+	s.Equal("http://b.com", p.FrameTree().ChildFrames[1].Frame.URL)
+
+	wait := p.EachFrameEvent(func(a *proto.PageFrameAttached, n *proto.PageFrameNavigated, d *proto.PageFrameDetached) bool {
+		return n != nil && n.Frame.ID == "oopif"
+	})
+	fake.Emit("other", "Page.frameNavigated", &proto.PageFrameNavigated{Frame: &proto.PageFrame{ID: "x"}})
+	fake.Emit("f", "Page.frameNavigated", &proto.PageFrameNavigated{Frame: &proto.PageFrame{ID: "oopif"}})
+	wait()
+
+	// creating the frame page has no round trip
+	fake.Reset()
+	same := p.ElementFromObject("same").Frame()
+	oopif := p.ElementFromObject("oopif").Frame()
+	s.Len(fake.Calls(""), 0)
+
+	s.True(same.IsIframe())
+	id, err := same.getExecutionID(false)
+	s.Nil(err)
+	s.EqualValues(1, id)
+	s.False(same.isOutOfProcess())
+	s.Equal(0, fake.Called("Target.getTargetInfo"))
+
+	_, err = oopif.getExecutionID(false)
+	s.Nil(err)
+	s.True(oopif.isOutOfProcess())
+	s.Equal(proto.TargetSessionID("f"), oopif.SessionID)
+	s.Equal(p, oopif.Root())
+
+	// the box is relative to the main frame, the iframe document starts at the content box of the iframe
+	box := oopif.ElementFromObject("btn").Box()
+	s.Equal(11.0, box.X)
+	s.Equal(11.0, box.Y)
+
+	// the iframe is attached before the auto-attach event arrives
+	late := p.ElementFromObject("late").Frame()
+	_, err = late.getExecutionID(false)
+	s.Nil(err)
+	s.Equal(proto.TargetSessionID("l"), late.SessionID)
+	s.Equal(1, fake.Called("Target.getTargetInfo"))
+
+	// the session can be switched while the iframe page is in use
+	shared := p.ElementFromObject("oopif").Frame()
+	done := make(chan struct{})
+	go func() {
+		_, _ = shared.getExecutionID(false)
+		close(done)
+	}()
+	_, _, _ = shared.Timeout(time.Second).CallContext()
+	<-done
+	s.Equal(proto.TargetSessionID("f"), shared.sessionID())
+
+	fake.Emit("s", "Target.detachedFromTarget", &proto.TargetDetachedFromTarget{SessionID: "f"})
+	s.waitUntil(func() bool {
+		_, has := p.frames.sessions.Load(proto.PageFrameID("oopif"))
+		return !has
+	})
+}
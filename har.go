@@ -0,0 +1,474 @@
+package rod
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/defaults"
+	"github.com/go-rod/rod/lib/har"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+	"github.com/ysmood/kit"
+)
+
+// HARRecorder records the network traffic of a page as HAR 1.2
+type HARRecorder struct {
+	page *Page
+	stop func()
+	done chan kit.Nil
+
+	lock    *sync.Mutex
+	bodies  *sync.WaitGroup // the response bodies that are being fetched
+	har     *har.HAR
+	pending map[proto.NetworkRequestID]*harPending
+	current *harPage
+}
+
+type harPending struct {
+	entry     *har.Entry
+	timestamp time.Duration // the monotonic time when the request is sent
+	timing    *proto.NetworkResourceTiming
+}
+
+type harPage struct {
+	page      *har.Page
+	timestamp time.Duration // the monotonic time when the page starts to load
+}
+
+// RecordHAR starts to record the network traffic of the page until HARRecorder.Stop is called.
+// The response bodies are fetched via Network.getResponseBody as soon as they finish loading.
+func (p *Page) RecordHAR() *HARRecorder {
+	ctx, cancel := context.WithCancel(p.ctx)
+
+	r := &HARRecorder{
+		page:    p,
+		stop:    cancel,
+		done:    make(chan kit.Nil),
+		lock:    &sync.Mutex{},
+		bodies:  &sync.WaitGroup{},
+		har:     har.New("rod", defaults.Version),
+		pending: map[proto.NetworkRequestID]*harPending{},
+	}
+
+	s := p.browser.event.Subscribe(ctx)
+	recoverNetwork := p.EnableDomain(&proto.NetworkEnable{})
+	recoverPage := p.EnableDomain(&proto.PageEnable{})
+
+	go func() {
+		defer close(r.done)
+		defer recoverPage()
+		defer recoverNetwork()
+		defer r.bodies.Wait() // the bodies can only be fetched before the Network domain is disabled
+
+		for msg := range s {
+			e := msg.(*cdp.Event)
+			if e.SessionID != string(p.browser.currentSession(p.sessionID())) {
+				continue
+			}
+			r.handle(e)
+		}
+	}()
+
+	return r
+}
+
+// Stop recording and returns the archive, it waits for the response bodies that are being fetched
+func (r *HARRecorder) Stop() *har.HAR {
+	r.stop()
+	<-r.done
+	return r.HAR()
+}
+
+// HAR returns a snapshot of the archive, the requests that haven't got a response yet are excluded.
+// The snapshot is a deep copy, it won't change with the recording.
+func (r *HARRecorder) HAR() *har.HAR {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	h := har.New(r.har.Log.Creator.Name, r.har.Log.Creator.Version)
+	for _, p := range r.har.Log.Pages {
+		h.Log.Pages = append(h.Log.Pages, p.Clone())
+	}
+	for _, e := range r.har.Log.Entries {
+		if e.Response != nil {
+			h.Log.Entries = append(h.Log.Entries, e.Clone())
+		}
+	}
+	return h
+}
+
+func (r *HARRecorder) handle(e *cdp.Event) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch e.Method {
+	case (proto.NetworkRequestWillBeSent{}).MethodName():
+		var evt proto.NetworkRequestWillBeSent
+		if Event(e, &evt) {
+			r.requestWillBeSent(&evt)
+		}
+
+	case (proto.NetworkResponseReceived{}).MethodName():
+		var evt proto.NetworkResponseReceived
+		if Event(e, &evt) {
+			if pending, has := r.pending[evt.RequestID]; has {
+				pending.entry.Response = harResponse(evt.Response)
+				pending.timing = evt.Response.Timing
+				pending.entry.ServerIPAddress = evt.Response.RemoteIPAddress
+				pending.entry.Connection = fmt.Sprint(evt.Response.ConnectionID)
+			}
+		}
+
+	case (proto.NetworkLoadingFinished{}).MethodName():
+		var evt proto.NetworkLoadingFinished
+		if Event(e, &evt) {
+			r.loadingFinished(&evt)
+		}
+
+	case (proto.NetworkLoadingFailed{}).MethodName():
+		var evt proto.NetworkLoadingFailed
+		if Event(e, &evt) {
+			if pending, has := r.pending[evt.RequestID]; has {
+				if pending.entry.Response == nil {
+					pending.entry.Response = harResponse(&proto.NetworkResponse{})
+				}
+				pending.entry.Response.StatusText = evt.ErrorText
+				r.finish(pending, monotonic(evt.Timestamp))
+				delete(r.pending, evt.RequestID)
+			}
+		}
+
+	case (proto.PageDomContentEventFired{}).MethodName():
+		var evt proto.PageDomContentEventFired
+		if Event(e, &evt) && r.current != nil {
+			r.current.page.PageTimings.OnContentLoad = toMillisecond(monotonic(evt.Timestamp) - r.current.timestamp)
+		}
+
+	case (proto.PageLoadEventFired{}).MethodName():
+		var evt proto.PageLoadEventFired
+		if Event(e, &evt) && r.current != nil {
+			r.current.page.PageTimings.OnLoad = toMillisecond(monotonic(evt.Timestamp) - r.current.timestamp)
+		}
+	}
+}
+
+func (r *HARRecorder) requestWillBeSent(e *proto.NetworkRequestWillBeSent) {
+	timestamp := monotonic(e.Timestamp)
+
+	// the redirects share the same request id
+	if pending, has := r.pending[e.RequestID]; has && e.RedirectResponse != nil {
+		pending.entry.Response = harResponse(e.RedirectResponse)
+		pending.entry.Response.RedirectURL = e.Request.URL
+		pending.timing = e.RedirectResponse.Timing
+		r.finish(pending, timestamp)
+	}
+
+	var started time.Time
+	if e.WallTime != nil {
+		started = e.WallTime.Time
+	}
+
+	// a navigation of the main frame starts a new page
+	if e.Type == proto.NetworkResourceTypeDocument &&
+		string(e.FrameID) == string(r.page.targetID()) &&
+		string(e.RequestID) == string(e.LoaderID) {
+
+		r.current = &harPage{
+			page: &har.Page{
+				StartedDateTime: started,
+				ID:              fmt.Sprintf("page_%d", len(r.har.Log.Pages)+1),
+				Title:           e.Request.URL,
+				PageTimings:     &har.PageTimings{OnContentLoad: -1, OnLoad: -1},
+			},
+			timestamp: timestamp,
+		}
+		r.har.Log.Pages = append(r.har.Log.Pages, r.current.page)
+	}
+
+	entry := &har.Entry{
+		StartedDateTime: started,
+		Request:         harRequest(e.Request),
+		Cache:           &har.Cache{},
+	}
+	if r.current != nil {
+		entry.Pageref = r.current.page.ID
+	}
+
+	r.pending[e.RequestID] = &harPending{entry: entry, timestamp: timestamp}
+	r.har.Log.Entries = append(r.har.Log.Entries, entry)
+}
+
+func (r *HARRecorder) loadingFinished(e *proto.NetworkLoadingFinished) {
+	pending, has := r.pending[e.RequestID]
+	if !has {
+		return
+	}
+	delete(r.pending, e.RequestID)
+
+	if pending.entry.Response == nil {
+		pending.entry.Response = harResponse(&proto.NetworkResponse{})
+	}
+	res := pending.entry.Response
+	res.BodySize = int64(e.EncodedDataLength)
+
+	// the body is fetched without the lock, so a slow body won't block the other events
+	r.bodies.Add(1)
+	go func() {
+		defer r.bodies.Done()
+
+		body, err := proto.NetworkGetResponseBody{RequestID: e.RequestID}.Call(r.page)
+
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		if err == nil {
+			res.Content.Text = body.Body
+			res.Content.Size = int64(len(body.Body))
+			if body.Base64Encoded {
+				res.Content.Encoding = "base64"
+				if b, err := res.Content.Body(); err == nil {
+					res.Content.Size = int64(len(b))
+				}
+			}
+		}
+
+		r.finish(pending, monotonic(e.Timestamp))
+	}()
+}
+
+// finish calculates the timings of the entry
+func (r *HARRecorder) finish(pending *harPending, end time.Duration) {
+	entry := pending.entry
+	t := pending.timing
+
+	if t == nil {
+		entry.Timings = &har.Timings{
+			Blocked: -1, DNS: -1, Connect: -1, SSL: -1,
+			Wait: toMillisecond(end - pending.timestamp),
+		}
+		entry.Time = entry.Timings.Wait
+		return
+	}
+
+	// the timing of the resource is relative to its requestTime in milliseconds
+	span := func(start, end float64) float64 {
+		if start < 0 || end < 0 {
+			return -1
+		}
+		return end - start
+	}
+
+	blocked := -1.0
+	for _, start := range []float64{t.DNSStart, t.ConnectStart, t.SendStart} {
+		if start >= 0 {
+			blocked = start
+			break
+		}
+	}
+
+	timings := &har.Timings{
+		Blocked: blocked,
+		DNS:     span(t.DNSStart, t.DNSEnd),
+		Connect: span(t.ConnectStart, t.ConnectEnd),
+		SSL:     span(t.SslStart, t.SslEnd),
+		Send:    span(t.SendStart, t.SendEnd),
+		Wait:    span(t.SendEnd, t.ReceiveHeadersEnd),
+		Receive: toMillisecond(end-time.Duration(t.RequestTime*float64(time.Second))) - t.ReceiveHeadersEnd,
+	}
+	for _, v := range []*float64{&timings.Send, &timings.Wait, &timings.Receive} {
+		if *v < 0 {
+			*v = 0
+		}
+	}
+	entry.Timings = timings
+
+	// ssl is included in connect
+	entry.Time = 0
+	for _, v := range []float64{timings.Blocked, timings.DNS, timings.Connect, timings.Send, timings.Wait, timings.Receive} {
+		if v > 0 {
+			entry.Time += v
+		}
+	}
+}
+
+func harRequest(req *proto.NetworkRequest) *har.Request {
+	u := req.URL + req.URLFragment
+	query := []*har.NameValue{}
+	if parsed, err := url.Parse(u); err == nil {
+		for k, vs := range parsed.Query() {
+			for _, v := range vs {
+				query = append(query, &har.NameValue{Name: k, Value: v})
+			}
+		}
+		sort.Slice(query, func(i, j int) bool { return query[i].Name < query[j].Name })
+	}
+
+	headers := harHeaders(req.Headers)
+
+	r := &har.Request{
+		Method:      req.Method,
+		URL:         u,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []*har.Cookie{},
+		Headers:     headers,
+		QueryString: query,
+		HeadersSize: -1,
+		BodySize:    int64(len(req.PostData)),
+	}
+
+	if req.HasPostData || req.PostData != "" {
+		mime := ""
+		for _, h := range headers {
+			if strings.EqualFold(h.Name, "Content-Type") {
+				mime = h.Value
+			}
+		}
+		r.PostData = &har.PostData{MimeType: mime, Params: []*har.NameValue{}, Text: req.PostData}
+	}
+
+	return r
+}
+
+func harResponse(res *proto.NetworkResponse) *har.Response {
+	return &har.Response{
+		Status:      res.Status,
+		StatusText:  res.StatusText,
+		HTTPVersion: harHTTPVersion(res.Protocol),
+		Cookies:     []*har.Cookie{},
+		Headers:     harHeaders(res.Headers),
+		Content:     &har.Content{MimeType: res.MIMEType},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+}
+
+// the values of the same header are joined with "\n"
+func harHeaders(headers proto.NetworkHeaders) []*har.NameValue {
+	list := []*har.NameValue{}
+	for k, v := range headers {
+		for _, item := range strings.Split(v.String(), "\n") {
+			list = append(list, &har.NameValue{Name: k, Value: item})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func harHTTPVersion(protocol string) string {
+	switch {
+	case protocol == "h2":
+		return "HTTP/2"
+	case strings.HasPrefix(protocol, "h3"), protocol == "quic":
+		return "HTTP/3"
+	case protocol == "":
+		return "HTTP/1.1"
+	}
+	return strings.ToUpper(protocol)
+}
+
+func monotonic(t *proto.MonotonicTime) time.Duration {
+	if t == nil {
+		return 0
+	}
+	return t.Duration
+}
+
+func toMillisecond(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// HAROptions for HijackRouter.AddHARE
+type HAROptions struct {
+	// MatchBody also requires the request body to be the same as the recorded one
+	MatchBody bool
+
+	// Fallthrough sends the unmatched requests to the real destination,
+	// by default they fail with proto.NetworkErrorReasonInternetDisconnected.
+	Fallthrough bool
+}
+
+// AddHARE adds a handler that answers the requests from the archive, the requests are matched by
+// method and URL. If an url is recorded several times, the responses are used in the recorded order,
+// the last one will be reused after all of them are used.
+func (r *HijackRouter) AddHARE(pattern string, archive *har.HAR, opts *HAROptions) error {
+	if opts == nil {
+		opts = &HAROptions{}
+	}
+
+	lock := &sync.Mutex{}
+	entries := map[string][]*har.Entry{}
+	for _, e := range archive.Log.Entries {
+		if e.Request == nil || e.Response == nil {
+			continue
+		}
+		key := harKey(e.Request.Method, e.Request.URL)
+		entries[key] = append(entries[key], e)
+	}
+
+	return r.AddE(pattern, "", func(ctx *Hijack) {
+		req := ctx.Request.event.Request
+		key := harKey(req.Method, req.URL+req.URLFragment)
+
+		lock.Lock()
+		var entry *har.Entry
+		for i, e := range entries[key] {
+			if opts.MatchBody && (e.Request.PostData == nil && req.PostData != "" ||
+				e.Request.PostData != nil && e.Request.PostData.Text != req.PostData) {
+				continue
+			}
+			entry = e
+			if len(entries[key]) > 1 {
+				entries[key] = append(entries[key][:i:i], entries[key][i+1:]...)
+			}
+			break
+		}
+		lock.Unlock()
+
+		if entry == nil {
+			if opts.Fallthrough {
+				ctx.ContinueRequest(&proto.FetchContinueRequest{})
+			} else {
+				ctx.Response.Fail(proto.NetworkErrorReasonInternetDisconnected)
+			}
+			return
+		}
+
+		res := entry.Response
+		if res.Status == 0 {
+			ctx.Response.Fail(proto.NetworkErrorReasonFailed)
+			return
+		}
+
+		body, err := res.Content.Body()
+		if err != nil {
+			ctx.OnError(err)
+			ctx.Response.Fail(proto.NetworkErrorReasonFailed)
+			return
+		}
+
+		ctx.Response.SetStatusCode(int(res.Status))
+		for _, h := range res.Headers {
+			// the recorded body is already decoded
+			if isBodyEncodingHeader(h.Name) {
+				continue
+			}
+			ctx.Response.SetHeader(h.Name, h.Value)
+		}
+		ctx.Response.SetBody(body)
+	})
+}
+
+// AddHAR is similar to AddHARE, the unmatched requests will fail
+func (r *HijackRouter) AddHAR(pattern string, archive *har.HAR) {
+	utils.E(r.AddHARE(pattern, archive, nil))
+}
+
+func harKey(method, u string) string {
+	return method + " " + u
+}
@@ -0,0 +1,189 @@
+package rod
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/har"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/kit"
+)
+
+func (s *S) TestRecordHAR() {
+	fake := newFake().
+		Handle("Target.createTarget", cdptest.Result(&proto.TargetCreateTargetResult{TargetID: "t"})).
+		Handle("Network.getResponseBody", cdptest.Result(&proto.NetworkGetResponseBodyResult{
+			Body: "aGk=", Base64Encoded: true,
+		}))
+
+	p := s.newFakePage(fake)
+	r := p.RecordHAR()
+
+	ts := func(t float64) *proto.MonotonicTime {
+		return &proto.MonotonicTime{Duration: time.Duration(t * float64(time.Second))}
+	}
+	req := &proto.NetworkRequest{URL: "http://a.com/?b=1&a=2", Method: "GET", Headers: proto.NetworkHeaders{
+		"Accept": proto.NewJSON("x\ny"),
+	}}
+
+	fake.Emit("other", "Network.requestWillBeSent", &proto.NetworkRequestWillBeSent{RequestID: "0", Request: req})
+	fake.Emit("s", "Network.requestWillBeSent", &proto.NetworkRequestWillBeSent{
+		RequestID: "1", LoaderID: "1", FrameID: "t", Type: proto.NetworkResourceTypeDocument,
+		Request: req, Timestamp: ts(1), WallTime: &proto.TimeSinceEpoch{Time: time.Unix(100, 0)},
+	})
+	fake.Emit("s", "Network.requestWillBeSent", &proto.NetworkRequestWillBeSent{
+		RequestID: "1", LoaderID: "1", Type: proto.NetworkResourceTypeDocument,
+		Request:          &proto.NetworkRequest{URL: "http://b.com", Method: "GET"},
+		RedirectResponse: &proto.NetworkResponse{Status: 302, Protocol: "h2"},
+		Timestamp:        ts(1.1),
+	})
+	fake.Emit("s", "Network.responseReceived", &proto.NetworkResponseReceived{
+		RequestID: "1", Response: &proto.NetworkResponse{Status: 200, MIMEType: "text/plain", Timing: &proto.NetworkResourceTiming{
+			RequestTime: 1.1, DNSStart: -1, DNSEnd: -1, ConnectStart: -1, ConnectEnd: -1, SslStart: -1, SslEnd: -1,
+			SendStart: 1, SendEnd: 2, ReceiveHeadersEnd: 10,
+		}},
+	})
+	fake.Emit("s", "Page.loadEventFired", &proto.PageLoadEventFired{Timestamp: ts(1.5)})
+	fake.Emit("s", "Network.loadingFinished", &proto.NetworkLoadingFinished{RequestID: "1", Timestamp: ts(1.2), EncodedDataLength: 5})
+
+	s.waitUntil(func() bool {
+		list := r.HAR().Log.Entries
+		return len(list) == 2 && list[1].Timings != nil
+	})
+	// the snapshot won't be changed by the caller or the recording
+	snapshot := r.HAR()
+	snapshot.Log.Entries[1].Response.Status = 0
+	s.EqualValues(200, r.HAR().Log.Entries[1].Response.Status)
+
+	h := r.Stop()
+
+	s.Len(h.Log.Pages, 1)
+	s.Equal(500.0, h.Log.Pages[0].PageTimings.OnLoad)
+	s.Len(h.Log.Entries, 2)
+
+	redirect := h.Log.Entries[0]
+	s.Equal("page_1", redirect.Pageref)
+	s.Equal(time.Unix(100, 0), redirect.StartedDateTime)
+	s.Equal("a", redirect.Request.QueryString[0].Name)
+	s.Equal("y", redirect.Request.Headers[1].Value)
+	s.EqualValues(302, redirect.Response.Status)
+	s.Equal("HTTP/2", redirect.Response.HTTPVersion)
+	s.Equal("http://b.com", redirect.Response.RedirectURL)
+	s.InDelta(100, redirect.Time, 0.001)
+
+	entry := h.Log.Entries[1]
+	s.EqualValues(200, entry.Response.Status)
+	s.EqualValues(2, entry.Response.Content.Size)
+	s.Equal("base64", entry.Response.Content.Encoding)
+	s.EqualValues(5, entry.Response.BodySize)
+	s.Equal(-1.0, entry.Timings.DNS)
+	s.InDelta(1, entry.Timings.Blocked, 0.001)
+	s.InDelta(8, entry.Timings.Wait, 0.001)
+	s.InDelta(90, entry.Timings.Receive, 0.001)
+	s.InDelta(100, entry.Time, 0.001)
+}
+
+func (s *S) TestHijackHAR() {
+	fake := newFake()
+	b := s.connectFake(fake)
+
+	entry := func(method, u, body string, status int64) *har.Entry {
+		return &har.Entry{
+			Request: &har.Request{Method: method, URL: u, PostData: &har.PostData{Text: body}},
+			Response: &har.Response{Status: status, Headers: []*har.NameValue{
+				{Name: "Content-Encoding", Value: "gzip"}, {Name: "X", Value: body},
+			}, Content: &har.Content{Text: body}},
+		}
+	}
+	archive := har.New("rod", "")
+	archive.Log.Entries = append(archive.Log.Entries,
+		entry("GET", "http://a.com/", "1", 200),
+		entry("GET", "http://a.com/", "2", 201),
+		entry("POST", "http://a.com/", "3", 200),
+		entry("GET", "http://a.com/failed", "", 0),
+		entry("POST", "http://a.com/y", "3", 200),
+	)
+
+	router := b.HijackRequests()
+	router.AddHAR("http://a.com/", archive)
+	s.Nil(router.AddHARE("http://a.com/?*", archive, &HAROptions{MatchBody: true, Fallthrough: true}))
+	go router.Run()
+
+	pause := func(id, method, u, body string) *cdptest.Call {
+		fake.Reset()
+		fake.Emit("", "Fetch.requestPaused", &proto.FetchRequestPaused{
+			RequestID: proto.FetchRequestID(id),
+			Request:   &proto.NetworkRequest{Method: method, URL: u, PostData: body},
+		})
+		var call *cdptest.Call
+		s.waitUntil(func() bool {
+			for _, c := range fake.Calls("") {
+				if c.JSONParams().Get("requestId").String() == id {
+					call = c
+					return true
+				}
+			}
+			return false
+		})
+		return call
+	}
+
+	call := pause("1", "GET", "http://a.com/", "")
+	s.Equal("Fetch.fulfillRequest", call.Method)
+	s.EqualValues(200, call.JSONParams().Get("responseCode").Int())
+	s.Equal(`[{"name":"X","value":"1"}]`, call.JSONParams().Get("responseHeaders").Raw)
+	s.EqualValues(201, pause("2", "GET", "http://a.com/", "").JSONParams().Get("responseCode").Int())
+	s.EqualValues(201, pause("3", "GET", "http://a.com/", "").JSONParams().Get("responseCode").Int())
+
+	// the body is ignored by default
+	s.Equal("Fetch.fulfillRequest", pause("4", "POST", "http://a.com/", "x").Method)
+
+	call = pause("5", "PUT", "http://a.com/", "")
+	s.Equal("Fetch.failRequest", call.Method)
+	s.Equal("InternetDisconnected", call.JSONParams().Get("errorReason").String())
+
+	s.Equal("Fetch.failRequest", pause("6", "GET", "http://a.com/failed", "").Method)
+	s.Equal("Fetch.continueRequest", pause("7", "POST", "http://a.com/x", "3").Method)
+	s.Equal("Fetch.continueRequest", pause("8", "POST", "http://a.com/y", "4").Method)
+	s.Equal("Fetch.fulfillRequest", pause("9", "POST", "http://a.com/y", "3").Method)
+}
+
+func (s *S) TestRecordHARPendingBody() {
+	fetching := make(chan kit.Nil)
+	release := make(chan kit.Nil)
+
+	fake := newFake().Handle("Network.getResponseBody", cdptest.Result(&proto.NetworkGetResponseBodyResult{Body: "hi"}))
+	b := s.connectFake(fake)
+	b.Use(func(next CDPCall) CDPCall {
+		return func(ctx context.Context, sessionID, method string, params interface{}) ([]byte, error) {
+			if method == (proto.NetworkGetResponseBody{}).MethodName() {
+				close(fetching)
+				<-release
+			}
+			return next(ctx, sessionID, method, params)
+		}
+	})
+
+	r := b.Page("").RecordHAR()
+	fake.Emit("s", "Network.requestWillBeSent", &proto.NetworkRequestWillBeSent{
+		RequestID: "1", Request: &proto.NetworkRequest{URL: "http://a.com", Method: "GET"},
+	})
+	fake.Emit("s", "Network.responseReceived", &proto.NetworkResponseReceived{
+		RequestID: "1", Response: &proto.NetworkResponse{Status: 200},
+	})
+	fake.Emit("s", "Network.loadingFinished", &proto.NetworkLoadingFinished{RequestID: "1"})
+	<-fetching
+
+	stopped := make(chan *har.HAR)
+	go func() { stopped <- r.Stop() }()
+
+	// the Network domain is kept until the pending body is fetched
+	time.Sleep(100 * time.Millisecond)
+	s.Equal(0, fake.Called("Network.disable"))
+
+	close(release)
+	h := <-stopped
+	s.Equal("hi", h.Log.Entries[0].Response.Content.Text)
+	s.Equal(1, fake.Called("Network.disable"))
+}
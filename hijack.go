@@ -3,6 +3,7 @@ package rod
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -12,7 +13,9 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 
+	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/rod/lib/utils"
 	"github.com/tidwall/gjson"
@@ -36,6 +39,11 @@ type HijackRouter struct {
 	run        func()
 	stopEvents func()
 	handlers   []*hijackHandler
+	routes     []*MockRoute
+	auth       AuthProvider
+	pending    *sync.WaitGroup // the auth challenges being answered
+	stopped    bool
+	lock       *sync.Mutex
 	enable     *proto.FetchEnable
 	caller     proto.Caller
 	browser    *Browser
@@ -47,6 +55,8 @@ func newHijackRouter(browser *Browser, caller proto.Caller) *HijackRouter {
 		browser:  browser,
 		caller:   caller,
 		handlers: []*hijackHandler{},
+		pending:  &sync.WaitGroup{},
+		lock:     &sync.Mutex{},
 	}
 }
 
@@ -57,65 +67,175 @@ func (r *HijackRouter) initEvents() *HijackRouter {
 
 	_ = r.enable.Call(r.caller)
 
-	r.run = r.browser.eachEvent(eventCtx, proto.TargetSessionID(sessionID), func(e *proto.FetchRequestPaused) bool {
-		go func() {
-			ctx := r.new(eventCtx, e)
-			for _, h := range r.handlers {
-				if h.regexp.MatchString(e.Request.URL) {
-					h.handler(ctx)
-
-					if ctx.continueRequest != nil {
-						ctx.continueRequest.RequestID = e.RequestID
-						err := ctx.continueRequest.Call(r.caller)
-						if err != nil {
-							ctx.OnError(err)
-						}
-						return
-					}
-
-					if ctx.Skip {
-						continue
-					}
-
-					if ctx.Response.fail.ErrorReason != "" {
-						err := ctx.Response.fail.Call(r.caller)
-						if err != nil {
-							ctx.OnError(err)
-						}
-						return
-					}
-
-					err := ctx.Response.payload.Call(r.caller)
-					if err != nil {
-						ctx.OnError(err)
-						return
-					}
-				}
+	s := r.browser.event.Subscribe(eventCtx)
+	session := proto.TargetSessionID(sessionID)
+
+	r.run = func() {
+		for msg := range s {
+			e := msg.(*cdp.Event)
+			if e.SessionID != string(r.browser.currentSession(session)) {
+				continue
 			}
-		}()
 
-		return false
-	})
+			paused := &proto.FetchRequestPaused{}
+			auth := &proto.FetchAuthRequired{}
+			if Event(e, paused) {
+				go r.handle(eventCtx, paused)
+			} else if Event(e, auth) && r.track() {
+				go r.handleAuth(auth)
+			}
+		}
+	}
 	return r
 }
 
+// track the auth challenge as pending, returns false if the router is stopped
+func (r *HijackRouter) track() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.stopped {
+		return false
+	}
+	r.pending.Add(1)
+	return true
+}
+
+// handle the paused request with the handlers, if no handler takes it, it will be continued as is
+func (r *HijackRouter) handle(eventCtx context.Context, e *proto.FetchRequestPaused) {
+	ctx := r.new(eventCtx, e)
+	defer ctx.Response.closeStream()
+
+	atResponse := ctx.Response.atResponseStage()
+	for _, h := range r.list() {
+		if h.match(e, atResponse) && h.claim() {
+			ctx.Skip = false
+			h.handler(ctx)
+
+			if h.once {
+				if ctx.Skip {
+					h.release()
+				} else {
+					r.remove(func(item *hijackHandler) bool { return item == h })
+				}
+			}
+
+			if ctx.continueRequest != nil {
+				ctx.continueRequest.RequestID = e.RequestID
+				err := ctx.continueRequest.Call(r.caller)
+				if err != nil {
+					ctx.OnError(err)
+				}
+				return
+			}
+
+			if ctx.Skip {
+				continue
+			}
+
+			if ctx.Response.fail.ErrorReason != "" {
+				err := ctx.Response.fail.Call(r.caller)
+				if err != nil {
+					ctx.OnError(err)
+				}
+				return
+			}
+
+			err := ctx.Response.fulfill()
+			if err != nil {
+				ctx.OnError(err)
+			}
+			return
+		}
+	}
+
+	err := proto.FetchContinueRequest{RequestID: e.RequestID}.Call(r.caller)
+	if err != nil {
+		ctx.OnError(err)
+	}
+}
+
 // AddE a hijack handler to router, the doc of the pattern is the same as "proto.FetchRequestPattern.URLPattern".
 // You can add new handler even after the "Run" is called.
 func (r *HijackRouter) AddE(pattern string, resourceType proto.NetworkResourceType, handler func(*Hijack)) error {
-	r.enable.Patterns = append(r.enable.Patterns, &proto.FetchRequestPattern{
-		URLPattern:   pattern,
-		ResourceType: resourceType,
-	})
+	return r.HandleE(pattern, &HijackOptions{ResourceType: resourceType}, handler)
+}
 
-	reg := regexp.MustCompile(proto.PatternToReg(pattern))
+// AddPatternE is similar to AddE, but it accepts the full request pattern.
+// With the RequestStage set to proto.FetchRequestStageResponse, the handler can read the real response
+// from the server via Hijack.Response and rewrite it before the page receives it.
+// If the handler doesn't modify the response, it will pass to the page as is.
+func (r *HijackRouter) AddPatternE(pattern *proto.FetchRequestPattern, handler func(*Hijack)) error {
+	return r.HandleE(pattern.URLPattern, &HijackOptions{
+		ResourceType: pattern.ResourceType,
+		RequestStage: pattern.RequestStage,
+	}, handler)
+}
 
-	r.handlers = append(r.handlers, &hijackHandler{
-		pattern: pattern,
-		regexp:  reg,
-		handler: handler,
-	})
+// HijackOptions of a hijack handler
+type HijackOptions struct {
+	// ID of the handler, it can be used to remove the handler via HijackRouter.RemoveID.
+	// The existing handler with the same ID will be replaced.
+	ID string
 
-	return r.enable.Call(r.caller)
+	// Priority of the handler, the handlers with higher priority will run first,
+	// the handlers with the same priority run in the order they are added.
+	Priority int
+
+	// ResourceType of the requests to handle, empty means all types
+	ResourceType proto.NetworkResourceType
+
+	// RequestStage to intercept, the doc is the same as "proto.FetchRequestPattern.RequestStage"
+	RequestStage proto.FetchRequestStage
+
+	// Once removes the handler after it handles a request, skipped requests don't count
+	Once bool
+}
+
+// HandleE adds a hijack handler with options to router, the doc of the pattern is the same as
+// "proto.FetchRequestPattern.URLPattern". If opts is nil, the default options will be used.
+// You can add new handler even after the "Run" is called.
+func (r *HijackRouter) HandleE(pattern string, opts *HijackOptions, handler func(*Hijack)) error {
+	if opts == nil {
+		opts = &HijackOptions{}
+	}
+
+	h := &hijackHandler{
+		id: opts.ID,
+		pattern: &proto.FetchRequestPattern{
+			URLPattern:   pattern,
+			ResourceType: opts.ResourceType,
+			RequestStage: opts.RequestStage,
+		},
+		regexp:   regexp.MustCompile(proto.PatternToReg(pattern)),
+		priority: opts.Priority,
+		once:     opts.Once,
+		handler:  handler,
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	handlers := []*hijackHandler{}
+	for _, item := range r.handlers {
+		if h.id == "" || item.id != h.id {
+			handlers = append(handlers, item)
+		}
+	}
+
+	// keep the list sorted by priority
+	i := len(handlers)
+	for i > 0 && handlers[i-1].priority < h.priority {
+		i--
+	}
+	handlers = append(handlers[:i], append([]*hijackHandler{h}, handlers[i:]...)...)
+
+	return r.update(handlers)
+}
+
+// Handle adds a hijack handler with options to router
+func (r *HijackRouter) Handle(pattern string, opts *HijackOptions, handler func(*Hijack)) {
+	utils.E(r.HandleE(pattern, opts, handler))
 }
 
 // Add a hijack handler to router, the doc of the pattern is the same as "proto.FetchRequestPattern.URLPattern".
@@ -124,25 +244,80 @@ func (r *HijackRouter) Add(pattern string, handler func(*Hijack)) {
 	utils.E(r.AddE(pattern, "", handler))
 }
 
+// AddResponse adds a hijack handler to intercept the real responses of the requests that match the pattern
+func (r *HijackRouter) AddResponse(pattern string, handler func(*Hijack)) {
+	utils.E(r.AddPatternE(&proto.FetchRequestPattern{
+		URLPattern:   pattern,
+		RequestStage: proto.FetchRequestStageResponse,
+	}, handler))
+}
+
 // RemoveE handler via the pattern
 func (r *HijackRouter) RemoveE(pattern string) error {
-	patterns := []*proto.FetchRequestPattern{}
+	r.lock.Lock()
+	routes := []*MockRoute{}
+	for _, route := range r.routes {
+		if route.pattern != pattern {
+			routes = append(routes, route)
+		}
+	}
+	r.routes = routes
+	r.lock.Unlock()
+
+	return r.remove(func(h *hijackHandler) bool {
+		return h.pattern.URLPattern == pattern
+	})
+}
+
+// Remove handler via the pattern
+func (r *HijackRouter) Remove(pattern string) {
+	utils.E(r.RemoveE(pattern))
+}
+
+// RemoveIDE removes the handler via the ID of the HijackOptions
+func (r *HijackRouter) RemoveIDE(id string) error {
+	return r.remove(func(h *hijackHandler) bool {
+		return h.id == id
+	})
+}
+
+// RemoveID removes the handler via the ID of the HijackOptions
+func (r *HijackRouter) RemoveID(id string) {
+	utils.E(r.RemoveIDE(id))
+}
+
+// remove the handlers that match the filter
+func (r *HijackRouter) remove(filter func(*hijackHandler) bool) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
 	handlers := []*hijackHandler{}
 	for _, h := range r.handlers {
-		if h.pattern != pattern {
-			patterns = append(patterns, &proto.FetchRequestPattern{URLPattern: h.pattern})
+		if !filter(h) {
 			handlers = append(handlers, h)
 		}
 	}
-	r.enable.Patterns = patterns
+
+	return r.update(handlers)
+}
+
+// update the handlers and the patterns to intercept, the lock should be held
+func (r *HijackRouter) update(handlers []*hijackHandler) error {
+	patterns := []*proto.FetchRequestPattern{}
+	for _, h := range handlers {
+		patterns = append(patterns, h.pattern)
+	}
 	r.handlers = handlers
+	r.enable.Patterns = patterns
 
 	return r.enable.Call(r.caller)
 }
 
-// Remove handler via the pattern
-func (r *HijackRouter) Remove(pattern string) {
-	utils.E(r.RemoveE(pattern))
+// list returns a snapshot of the handlers
+func (r *HijackRouter) list() []*hijackHandler {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.handlers
 }
 
 // new context
@@ -158,17 +333,26 @@ func (r *HijackRouter) new(ctx context.Context, e *proto.FetchRequestPaused) *Hi
 		StringBody(e.Request.PostData).
 		Context(ctx)
 
+	payload := &proto.FetchFulfillRequest{
+		ResponseCode: 200,
+		RequestID:    e.RequestID,
+	}
+	if e.ResponseStatusCode != 0 { // the real response is the default one to override
+		payload.ResponseCode = e.ResponseStatusCode
+		payload.ResponseHeaders = append(payload.ResponseHeaders, e.ResponseHeaders...)
+	}
+
 	return &Hijack{
+		ctx: ctx,
 		Request: &HijackRequest{
 			event: e,
 			req:   req,
 		},
 		Response: &HijackResponse{
-			req: req,
-			payload: &proto.FetchFulfillRequest{
-				ResponseCode: 200,
-				RequestID:    e.RequestID,
-			},
+			req:     req,
+			event:   e,
+			caller:  r.caller,
+			payload: payload,
 			fail: &proto.FetchFailRequest{
 				RequestID: e.RequestID,
 			},
@@ -186,9 +370,14 @@ func (r *HijackRouter) Run() {
 	r.run()
 }
 
-// StopE the router
+// StopE the router, it waits for the auth challenges being answered
 func (r *HijackRouter) StopE() error {
+	r.lock.Lock()
+	r.stopped = true
+	r.lock.Unlock()
+
 	r.stopEvents()
+	r.pending.Wait()
 	return proto.FetchDisable{}.Call(r.caller)
 }
 
@@ -199,9 +388,36 @@ func (r *HijackRouter) Stop() {
 
 // hijackHandler to handle each request that match the regexp
 type hijackHandler struct {
-	pattern string
-	regexp  *regexp.Regexp
-	handler func(*Hijack)
+	id       string
+	pattern  *proto.FetchRequestPattern
+	regexp   *regexp.Regexp
+	priority int
+	once     bool
+	used     int32
+	handler  func(*Hijack)
+}
+
+func (h *hijackHandler) atResponseStage() bool {
+	return h.pattern.RequestStage == proto.FetchRequestStageResponse
+}
+
+func (h *hijackHandler) match(e *proto.FetchRequestPaused, atResponse bool) bool {
+	if h.atResponseStage() != atResponse {
+		return false
+	}
+	if h.pattern.ResourceType != "" && h.pattern.ResourceType != e.ResourceType {
+		return false
+	}
+	return h.regexp.MatchString(e.Request.URL)
+}
+
+// claim the once handler so that it won't handle requests concurrently
+func (h *hijackHandler) claim() bool {
+	return !h.once || atomic.CompareAndSwapInt32(&h.used, 0, 1)
+}
+
+func (h *hijackHandler) release() {
+	atomic.StoreInt32(&h.used, 0)
 }
 
 // Hijack context
@@ -213,6 +429,7 @@ type Hijack struct {
 	// Skip to next handler
 	Skip bool
 
+	ctx             context.Context
 	continueRequest *proto.FetchContinueRequest
 }
 
@@ -350,13 +567,27 @@ func (ctx *HijackRequest) SetClient(client *http.Client) *HijackRequest {
 
 // HijackResponse context
 type HijackResponse struct {
-	req     *kit.ReqContext
-	payload *proto.FetchFulfillRequest
-	fail    *proto.FetchFailRequest
+	req      *kit.ReqContext
+	event    *proto.FetchRequestPaused
+	caller   proto.Caller
+	payload  *proto.FetchFulfillRequest
+	fail     *proto.FetchFailRequest
+	body     io.Reader
+	stream   *streamReader
+	modified bool // the status code or headers are changed
+}
+
+// atResponseStage is true if the request is paused after the real response is received
+func (ctx *HijackResponse) atResponseStage() bool {
+	return ctx.event != nil && (ctx.event.ResponseStatusCode != 0 || ctx.event.ResponseErrorReason != "")
 }
 
 // StatusCodeE of response
 func (ctx *HijackResponse) StatusCodeE() (int, error) {
+	if ctx.atResponseStage() {
+		return int(ctx.event.ResponseStatusCode), nil
+	}
+
 	res, err := ctx.req.Response()
 	if err != nil {
 		return 0, err
@@ -374,17 +605,18 @@ func (ctx *HijackResponse) StatusCode() int {
 
 // SetStatusCode of response
 func (ctx *HijackResponse) SetStatusCode(code int) {
+	ctx.modified = true
 	ctx.payload.ResponseCode = int64(code)
 }
 
 // HeaderE via key
 func (ctx *HijackResponse) HeaderE(key string) (string, error) {
-	res, err := ctx.req.Response()
+	header, err := ctx.HeadersE()
 	if err != nil {
 		return "", err
 	}
 
-	return res.Header.Get(key), nil
+	return header.Get(key), nil
 }
 
 // Header via key
@@ -396,6 +628,14 @@ func (ctx *HijackResponse) Header(key string) string {
 
 // HeadersE of request
 func (ctx *HijackResponse) HeadersE() (http.Header, error) {
+	if ctx.atResponseStage() {
+		header := http.Header{}
+		for _, h := range ctx.event.ResponseHeaders {
+			header.Add(h.Name, h.Value)
+		}
+		return header, nil
+	}
+
 	res, err := ctx.req.Response()
 	if err != nil {
 		return nil, err
@@ -411,8 +651,10 @@ func (ctx *HijackResponse) Headers() http.Header {
 	return val
 }
 
-// SetHeader via key-value pairs
+// SetHeader via key-value pairs, each pair is appended as a header entry, so a key can have multiple values.
+// At the response stage, the headers of the real response that have the same keys will be replaced.
 func (ctx *HijackResponse) SetHeader(pairs ...string) {
+	ctx.modified = true
 	for i := 0; i < len(pairs); i += 2 {
 		ctx.payload.ResponseHeaders = append(ctx.payload.ResponseHeaders, &proto.FetchHeaderEntry{
 			Name:  pairs[i],
@@ -423,6 +665,21 @@ func (ctx *HijackResponse) SetHeader(pairs ...string) {
 
 // BodyE of response
 func (ctx *HijackResponse) BodyE() ([]byte, error) {
+	if ctx.stream != nil && ctx.stream.taken() {
+		return ioutil.ReadAll(ctx.stream)
+	}
+
+	if ctx.atResponseStage() {
+		res, err := proto.FetchGetResponseBody{RequestID: ctx.event.RequestID}.Call(ctx.caller)
+		if err != nil {
+			return nil, err
+		}
+		if res.Base64Encoded {
+			return base64.StdEncoding.DecodeString(res.Body)
+		}
+		return []byte(res.Body), nil
+	}
+
 	b, err := ctx.req.Bytes()
 	if err != nil {
 		return nil, err
@@ -438,8 +695,20 @@ func (ctx *HijackResponse) Body() []byte {
 	return b
 }
 
-// BodyStreamE returns the stream of the body
-func (ctx *HijackResponse) BodyStreamE() (io.Reader, error) {
+// BodyStreamE returns the stream of the body, reading it won't buffer the whole body in memory.
+// At the response stage the stream is taken from the browser on the first read. If the stream is passed
+// to SetBody without being read, the real body is used as is, it goes to the page directly if the status
+// and headers are not changed either.
+// Once the stream is read, the real body is consumed, the handler must set a new body via SetBody,
+// such as the stream itself, otherwise the request will fail with ErrBodyConsumed.
+func (ctx *HijackResponse) BodyStreamE() (io.ReadCloser, error) {
+	if ctx.atResponseStage() {
+		if ctx.stream == nil {
+			ctx.stream = &streamReader{caller: ctx.caller, requestID: ctx.event.RequestID}
+		}
+		return ctx.stream, nil
+	}
+
 	res, err := ctx.req.Response()
 	if err != nil {
 		return nil, err
@@ -448,7 +717,7 @@ func (ctx *HijackResponse) BodyStreamE() (io.Reader, error) {
 }
 
 // BodyStream returns the stream of the body
-func (ctx *HijackResponse) BodyStream() io.Reader {
+func (ctx *HijackResponse) BodyStream() io.ReadCloser {
 	body, err := ctx.BodyStreamE()
 	utils.E(err)
 	return body
@@ -464,9 +733,17 @@ func (ctx *HijackResponse) JSONBody() gjson.Result {
 	return gjson.ParseBytes(ctx.Body())
 }
 
-// SetBody of response, if obj is []byte, raw body will be used, else it will be encoded as json
+// SetBody of response, if obj is []byte, raw body will be used,
+// if obj is io.Reader, it will be read when the request is fulfilled and closed if it's an io.Closer,
+// else it will be encoded as json.
+// The protocol requires the whole body in one message to fulfill the request, so the reader will be read to
+// the end and held in memory as base64. To pass a large real body at the response stage, leave it untouched.
 func (ctx *HijackResponse) SetBody(obj interface{}) *HijackResponse {
+	ctx.body = nil
 	switch body := obj.(type) {
+	case io.Reader:
+		ctx.payload.Body = nil
+		ctx.body = body
 	case []byte:
 		ctx.payload.Body = body
 	case string:
@@ -480,6 +757,202 @@ func (ctx *HijackResponse) SetBody(obj interface{}) *HijackResponse {
 	return ctx
 }
 
+// fulfill the request with the payload. At the response stage the real response passes to the page as is
+// if the handler changes nothing, and the real body is used if the handler only changes the status or headers.
+func (ctx *HijackResponse) fulfill() error {
+	replaced := ctx.bodyReplaced()
+
+	if ctx.atResponseStage() && !replaced {
+		if ctx.stream != nil && ctx.stream.taken() {
+			ctx.fail.ErrorReason = proto.NetworkErrorReasonFailed
+			err := ctx.fail.Call(ctx.caller)
+			if err != nil {
+				return err
+			}
+			return newErr(ErrBodyConsumed, ctx.event.Request.URL)
+		}
+
+		if !ctx.modified {
+			return proto.FetchContinueRequest{RequestID: ctx.event.RequestID}.Call(ctx.caller)
+		}
+	}
+
+	ctx.dropStaleHeaders()
+
+	var body string
+	var err error
+	switch {
+	case ctx.body != nil && replaced:
+		body, err = encodeBody(ctx.body)
+		if closer, ok := ctx.body.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	case ctx.payload.Body != nil:
+		body = base64.StdEncoding.EncodeToString(ctx.payload.Body)
+	case ctx.atResponseStage():
+		body, err = ctx.realBody()
+	}
+	if err != nil {
+		return err
+	}
+
+	return proto.Call(ctx.payload.MethodName(), &fulfillRequest{ctx.payload, body}, nil, ctx.caller)
+}
+
+// bodyReplaced tells if the handler sets a body other than the untouched real one
+func (ctx *HijackResponse) bodyReplaced() bool {
+	if ctx.body != nil {
+		return ctx.stream == nil || ctx.body != io.Reader(ctx.stream) || ctx.stream.taken()
+	}
+	return ctx.payload.Body != nil
+}
+
+// realBody returns the real body of the response encoded as base64, the browser encodes it already
+func (ctx *HijackResponse) realBody() (string, error) {
+	res, err := proto.FetchGetResponseBody{RequestID: ctx.event.RequestID}.Call(ctx.caller)
+	if err != nil {
+		return "", err
+	}
+	if res.Base64Encoded {
+		return res.Body, nil
+	}
+	return base64.StdEncoding.EncodeToString([]byte(res.Body)), nil
+}
+
+// closeStream closes the stream of the real body if it's taken
+func (ctx *HijackResponse) closeStream() {
+	if ctx.stream != nil {
+		_ = ctx.stream.Close()
+	}
+}
+
+// fulfillRequest is the proto.FetchFulfillRequest whose body is already encoded as base64
+type fulfillRequest struct {
+	*proto.FetchFulfillRequest
+	Body string `json:"body,omitempty"`
+}
+
+// encodeBody reads the reader to the end and encodes it as base64
+func encodeBody(r io.Reader) (string, error) {
+	buf := &strings.Builder{}
+	enc := base64.NewEncoder(base64.StdEncoding, buf)
+	_, err := io.Copy(enc, r)
+	if err != nil {
+		return "", err
+	}
+	err = enc.Close()
+	return buf.String(), err
+}
+
+// dropStaleHeaders removes the headers of the real response that are replaced by the ones set by the handlers,
+// and the ones that describe how the real body is encoded, they are wrong for the fulfilled body,
+// even the real body is decoded when it's read.
+func (ctx *HijackResponse) dropStaleHeaders() {
+	if !ctx.atResponseStage() {
+		return
+	}
+
+	real := map[*proto.FetchHeaderEntry]bool{}
+	for _, h := range ctx.event.ResponseHeaders {
+		real[h] = true
+	}
+
+	set := map[string]bool{}
+	for _, h := range ctx.payload.ResponseHeaders {
+		if !real[h] {
+			set[http.CanonicalHeaderKey(h.Name)] = true
+		}
+	}
+
+	list := []*proto.FetchHeaderEntry{}
+	for _, h := range ctx.payload.ResponseHeaders {
+		if !real[h] || !(set[http.CanonicalHeaderKey(h.Name)] || isBodyEncodingHeader(h.Name)) {
+			list = append(list, h)
+		}
+	}
+	ctx.payload.ResponseHeaders = list
+}
+
+// isBodyEncodingHeader tells if the header describes how the body is encoded on the wire
+func isBodyEncodingHeader(name string) bool {
+	return strings.EqualFold(name, "Content-Encoding") || strings.EqualFold(name, "Content-Length")
+}
+
+// the minimum size of each IO.read, so that the small buffers of the callers won't cause too many round trips
+const streamReadSize = 1024 * 1024
+
+// streamReader reads the real body of a paused response via the IO domain chunk by chunk.
+// The body is taken as a stream on the first read.
+type streamReader struct {
+	caller    proto.Caller
+	requestID proto.FetchRequestID
+	handle    proto.IOStreamHandle
+	buf       []byte
+	eof       bool
+	closed    bool
+}
+
+func (s *streamReader) taken() bool {
+	return s.handle != ""
+}
+
+func (s *streamReader) Read(p []byte) (int, error) {
+	if s.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if !s.taken() {
+		res, err := proto.FetchTakeResponseBodyAsStream{RequestID: s.requestID}.Call(s.caller)
+		if err != nil {
+			return 0, err
+		}
+		s.handle = res.Stream
+	}
+
+	for len(s.buf) == 0 {
+		if s.eof {
+			return 0, io.EOF
+		}
+
+		size := len(p)
+		if size < streamReadSize {
+			size = streamReadSize
+		}
+
+		res, err := proto.IORead{Handle: s.handle, Size: int64(size)}.Call(s.caller)
+		if err != nil {
+			return 0, err
+		}
+		s.eof = res.EOF
+
+		if res.Base64Encoded {
+			s.buf, err = base64.StdEncoding.DecodeString(res.Data)
+			if err != nil {
+				return 0, err
+			}
+		} else {
+			s.buf = []byte(res.Data)
+		}
+	}
+
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+// Close the stream, it's safe to call it more than once
+func (s *streamReader) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if !s.taken() {
+		return nil
+	}
+	return proto.IOClose{Handle: s.handle}.Call(s.caller)
+}
+
 // Fail request
 func (ctx *HijackResponse) Fail(reason proto.NetworkErrorReason) *HijackResponse {
 	ctx.fail.ErrorReason = reason
@@ -487,7 +960,8 @@ func (ctx *HijackResponse) Fail(reason proto.NetworkErrorReason) *HijackResponse
 }
 
 // GetDownloadFileE of the next download url that matches the pattern, returns the file content.
-// The handler will be used once and removed.
+// The handler will be used once and removed. The file is fetched again via the router,
+// for POST-triggered downloads or large files use Page.Downloads instead.
 func (p *Page) GetDownloadFileE(pattern string, resourceType proto.NetworkResourceType) func() (http.Header, io.Reader, error) {
 	enable := p.DisableDomain(&proto.FetchEnable{})
 
@@ -596,6 +1070,7 @@ func (p *Page) GetDownloadFile(pattern string) func() []byte {
 
 // HandleAuthE for the next basic HTTP authentication.
 // It will prevent the popup that requires user to input user name and password.
+// Use Browser.HandleAuthWith to handle all the challenges with per-origin credentials.
 // Ref: https://developer.mozilla.org/en-US/docs/Web/HTTP/Authentication
 func (b *Browser) HandleAuthE(username, password string) func() error {
 	enable := b.DisableDomain(b.ctx, "", &proto.FetchEnable{})
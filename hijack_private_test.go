@@ -0,0 +1,221 @@
+package rod
+
+import (
+	"encoding/base64"
+	"sync/atomic"
+
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+func (s *S) TestHijackResponseStage() {
+	fake := newFake()
+	fake.Handle("Fetch.getResponseBody", cdptest.Result(&proto.FetchGetResponseBodyResult{
+		Body: "b2s=", Base64Encoded: true,
+	}))
+	b := s.connectFake(fake)
+
+	router := b.HijackRequests()
+	router.Add("*", func(ctx *Hijack) {
+		ctx.Response.SetBody("request stage")
+	})
+	router.AddResponse("http://a.com/*", func(ctx *Hijack) {
+		switch ctx.Request.URL().Path {
+		case "/skip":
+			return
+		case "/replace":
+			ctx.Response.SetBody("new")
+			ctx.Response.SetHeader("Content-Length", "3")
+			return
+		case "/cookies":
+			ctx.Response.SetHeader("Set-Cookie", "a=1")
+			ctx.Response.SetHeader("Set-Cookie", "b=2")
+			return
+		}
+		s.Equal(201, ctx.Response.StatusCode())
+		s.Equal("1", ctx.Response.Header("X"))
+		s.Equal("ok", ctx.Response.StringBody())
+		ctx.Response.SetHeader("x", "2")
+	})
+	go router.Run()
+
+	pause := func(id, u string) *cdptest.Call {
+		fake.Reset()
+		fake.Emit("", "Fetch.requestPaused", &proto.FetchRequestPaused{
+			RequestID:          proto.FetchRequestID(id),
+			Request:            &proto.NetworkRequest{Method: "GET", URL: u},
+			ResponseStatusCode: 201,
+			ResponseHeaders: []*proto.FetchHeaderEntry{
+				{Name: "X", Value: "1"}, {Name: "Y", Value: "1"},
+				{Name: "Content-Encoding", Value: "gzip"}, {Name: "content-length", Value: "10"},
+			},
+		})
+		var call *cdptest.Call
+		s.waitUntil(func() bool {
+			for _, c := range fake.Calls("") {
+				if c.Method != "Fetch.getResponseBody" && c.JSONParams().Get("requestId").String() == id {
+					call = c
+					return true
+				}
+			}
+			return false
+		})
+		return call
+	}
+
+	// unmodified responses pass as is
+	s.Equal("Fetch.continueRequest", pause("1", "http://a.com/skip").Method)
+
+	call := pause("2", "http://a.com/")
+	s.Equal("Fetch.fulfillRequest", call.Method)
+	s.EqualValues(201, call.JSONParams().Get("responseCode").Int())
+	s.Equal(`[{"name":"Y","value":"1"},{"name":"x","value":"2"}]`, call.JSONParams().Get("responseHeaders").Raw)
+	s.Equal("b2s=", call.JSONParams().Get("body").String())
+
+	// the encoding headers of the real body are dropped
+	call = pause("3", "http://a.com/replace")
+	s.Equal(`[{"name":"X","value":"1"},{"name":"Y","value":"1"},{"name":"Content-Length","value":"3"}]`,
+		call.JSONParams().Get("responseHeaders").Raw)
+	s.Equal("bmV3", call.JSONParams().Get("body").String())
+
+	// the values of the same key are all kept
+	call = pause("4", "http://a.com/cookies")
+	s.Equal(`[{"name":"X","value":"1"},{"name":"Y","value":"1"},`+
+		`{"name":"Set-Cookie","value":"a=1"},{"name":"Set-Cookie","value":"b=2"}]`,
+		call.JSONParams().Get("responseHeaders").Raw)
+}
+
+func (s *S) TestHijackStreamBody() {
+	chunks := []*proto.IOReadResult{
+		{Data: "YWI=", Base64Encoded: true},
+		{Data: "c"},
+		{Data: "", EOF: true},
+	}
+	var count int64
+	fake := newFake().
+		Handle("Fetch.takeResponseBodyAsStream", cdptest.Result(&proto.FetchTakeResponseBodyAsStreamResult{Stream: "h"})).
+		Handle("Fetch.getResponseBody", cdptest.Result(&proto.FetchGetResponseBodyResult{
+			Body: "YWJj", Base64Encoded: true,
+		})).
+		Handle("IO.read", func(*cdptest.Call) (interface{}, error) {
+			return chunks[atomic.AddInt64(&count, 1)-1], nil
+		})
+	b := s.connectFake(fake)
+
+	router := b.HijackRequests()
+	router.AddResponse("*", func(ctx *Hijack) {
+		stream := ctx.Response.BodyStream()
+		switch ctx.Request.URL().Path {
+		case "/header":
+			ctx.Response.SetHeader("X-A", "1")
+			ctx.Response.SetBody(stream)
+		case "/read":
+			_, _ = stream.Read(make([]byte, 1))
+			ctx.Response.SetBody(stream)
+		case "/consume":
+			_, _ = stream.Read(make([]byte, 1))
+		default:
+			ctx.Response.SetBody(stream)
+		}
+	})
+	go router.Run()
+
+	pause := func(path string) {
+		fake.Reset()
+		fake.Emit("", "Fetch.requestPaused", &proto.FetchRequestPaused{
+			RequestID:          "1",
+			Request:            &proto.NetworkRequest{Method: "GET", URL: "http://a.com" + path},
+			ResponseStatusCode: 200,
+		})
+	}
+
+	// the untouched body passes to the page directly
+	pause("/")
+	s.waitUntil(func() bool {
+		return fake.Called("Fetch.continueRequest") == 1
+	})
+	s.Equal(0, fake.Called("Fetch.takeResponseBodyAsStream"))
+	s.Equal(0, fake.Called("Fetch.fulfillRequest"))
+
+	// the untaken body is fulfilled as the browser encodes it, it won't be streamed and encoded again
+	pause("/header")
+	s.waitUntil(func() bool {
+		return fake.Called("Fetch.fulfillRequest") == 1
+	})
+	s.Equal("YWJj", fake.Calls("Fetch.fulfillRequest")[0].JSONParams().Get("body").String())
+	s.Equal(1, fake.Called("Fetch.getResponseBody"))
+	s.Equal(0, fake.Called("Fetch.takeResponseBodyAsStream"))
+
+	// the taken body is read via the IO domain
+	pause("/read")
+	s.waitUntil(func() bool {
+		return fake.Called("Fetch.fulfillRequest") == 1 && fake.Called("IO.close") == 1
+	})
+	s.Equal("YmM=", fake.Calls("Fetch.fulfillRequest")[0].JSONParams().Get("body").String())
+	s.Equal("h", fake.Calls("IO.read")[0].JSONParams().Get("handle").String())
+	s.GreaterOrEqual(fake.Calls("IO.read")[0].JSONParams().Get("size").Int(), int64(streamReadSize))
+
+	// the consumed body without a new one fails the request
+	atomic.StoreInt64(&count, 0)
+	pause("/consume")
+	s.waitUntil(func() bool {
+		return fake.Called("Fetch.failRequest") == 1 && fake.Called("IO.close") == 1
+	})
+	s.Equal(0, fake.Called("Fetch.fulfillRequest"))
+}
+
+func (s *S) TestHijackOptions() {
+	fake := newFake()
+	b := s.connectFake(fake)
+
+	reply := func(body string) func(*Hijack) {
+		return func(ctx *Hijack) { ctx.Response.SetBody(body) }
+	}
+
+	router := b.HijackRequests()
+	router.Add("*", reply("default"))
+	router.Handle("*", &HijackOptions{ID: "x", Priority: 1}, reply("x"))
+	router.Handle("*", &HijackOptions{ID: "x", Priority: 1}, reply("x2"))
+	router.Handle("*", &HijackOptions{Priority: 2, ResourceType: proto.NetworkResourceTypeImage}, reply("image"))
+	router.Handle("*", &HijackOptions{Priority: 3, Once: true}, func(ctx *Hijack) {
+		if ctx.Request.Type() == proto.NetworkResourceTypeScript {
+			ctx.Skip = true
+			return
+		}
+		ctx.Response.SetBody("once")
+	})
+	go router.Run()
+
+	s.Len(fake.Calls("Fetch.enable")[len(fake.Calls("Fetch.enable"))-1].JSONParams().Get("patterns").Array(), 4)
+
+	pause := func(id string, t proto.NetworkResourceType) string {
+		fake.Reset()
+		fake.Emit("", "Fetch.requestPaused", &proto.FetchRequestPaused{
+			RequestID:    proto.FetchRequestID(id),
+			Request:      &proto.NetworkRequest{Method: "GET", URL: "http://a.com"},
+			ResourceType: t,
+		})
+		var body string
+		s.waitUntil(func() bool {
+			for _, c := range fake.Calls("Fetch.fulfillRequest") {
+				if c.JSONParams().Get("requestId").String() == id {
+					b, err := base64.StdEncoding.DecodeString(c.JSONParams().Get("body").String())
+					utils.E(err)
+					body = string(b)
+					return true
+				}
+			}
+			return false
+		})
+		return body
+	}
+
+	s.Equal("x2", pause("1", proto.NetworkResourceTypeScript))
+	s.Equal("once", pause("2", proto.NetworkResourceTypeImage))
+	s.Equal("image", pause("3", proto.NetworkResourceTypeImage))
+	s.Equal("x2", pause("4", proto.NetworkResourceTypeXHR))
+
+	router.RemoveID("x")
+	s.Equal("default", pause("5", proto.NetworkResourceTypeXHR))
+}
@@ -1,9 +1,12 @@
 package rod_test
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
@@ -147,6 +150,185 @@ func (s *S) TestHijackFailRequest() {
 	s.Equal("Failed to fetch", s.page.Element("body").Text())
 }
 
+func (s *S) TestHijackHAR() {
+	url, engine, close := serve()
+
+	engine.GET("/", ginHTML(`<html>
+	<body></body>
+	<script>
+		fetch('/a', { method: 'POST', body: 'x' }).then(async (res) => {
+			document.body.innerText = await res.text()
+		}).catch((err) => {
+			document.body.innerText = err.message
+		})
+	</script></html>`))
+	engine.POST("/a", ginString(`ok`))
+
+	page := s.browser.Page("")
+	defer page.Close()
+
+	r := page.RecordHAR()
+	page.Navigate(url)
+	s.Equal("ok", page.Element("body").Text())
+	archive := r.Stop()
+
+	close() // the server is gone, the responses can only come from the archive
+
+	router := page.HijackRequests()
+	defer router.Stop()
+	router.AddHAR(url+"/*", archive)
+	go router.Run()
+
+	page.Navigate(url)
+	s.Equal("ok", page.Element("body").Text())
+
+	// the body doesn't match
+	archive.Log.Entries[1].Request.PostData.Text = "y"
+	router.Remove(url + "/*")
+	utils.E(router.AddHARE(url+"/*", archive, &rod.HAROptions{MatchBody: true}))
+
+	page.Navigate(url)
+	s.Equal("Failed to fetch", page.Element("body").Text())
+}
+
+func (s *S) TestHijackResponse() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/", ginHTML(`<html>
+	<body></body>
+	<script>
+		fetch('/a').then(async (res) => {
+			document.body.innerText = res.status + res.headers.get('x') + await res.text()
+		})
+	</script></html>`))
+	engine.GET("/a", func(ctx kit.GinContext) {
+		ctx.Header("x", "1")
+		ctx.String(201, "ok")
+	})
+
+	page := s.browser.Page("")
+	defer page.Close()
+
+	router := page.HijackRequests()
+	defer router.Stop()
+
+	router.AddResponse(url+"/a", func(ctx *rod.Hijack) {
+		s.Equal(201, ctx.Response.StatusCode())
+		s.Equal("1", ctx.Response.Header("x"))
+		ctx.Response.SetHeader("x", "2")
+		ctx.Response.SetBody(ctx.Response.StringBody() + "!")
+	})
+	go router.Run()
+
+	page.Navigate(url)
+	s.Equal("2012ok!", page.Element("body").Text())
+}
+
+func (s *S) TestHijackResponseStream() {
+	url, engine, close := serve()
+	defer close()
+
+	size := 10 * 1024 * 1024
+
+	engine.GET("/", ginHTML(`<html>
+	<body></body>
+	<script>
+		fetch('/a').then(async (res) => {
+			document.body.innerText = (await res.arrayBuffer()).byteLength
+		})
+	</script></html>`))
+	engine.GET("/a", func(ctx kit.GinContext) {
+		ctx.Data(200, "application/octet-stream", bytes.Repeat([]byte("a"), size))
+	})
+
+	page := s.browser.Page("")
+	defer page.Close()
+
+	router := page.HijackRequests()
+	defer router.Stop()
+
+	router.AddResponse(url+"/a", func(ctx *rod.Hijack) {
+		ctx.Response.SetBody(ctx.Response.BodyStream())
+	})
+	go router.Run()
+
+	page.Navigate(url)
+	s.Equal(strconv.Itoa(size), page.Element("body").Text())
+}
+
+func (s *S) TestHijackRoute() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/", ginHTML(`<html>
+	<body></body>
+	<script>
+		fetch('/api/users?name=jack', {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ age: 10 }),
+		}).then(async (res) => {
+			document.body.innerText = res.status + (await res.json()).name
+		})
+	</script></html>`))
+
+	page := s.browser.Page("")
+	defer page.Close()
+
+	router := page.HijackRequests()
+	defer router.Stop()
+
+	router.Route("POST", url+"/api/*").Query("name", "jack").JSON("age", 10).
+		ReplyTemplate(201, `{"name":"{{.Request.URL.Query.Get "name"}}"}`, nil).
+		Times(1)
+	router.Add("*", func(ctx *rod.Hijack) {
+		ctx.ContinueRequest(&proto.FetchContinueRequest{})
+	})
+	go router.Run()
+
+	s.True(errors.Is(router.VerifyE(), rod.ErrMockExpectation))
+
+	page.Navigate(url)
+	s.Equal("201jack", page.Element("body").Text())
+	router.Verify()
+}
+
+func (s *S) TestHijackOptions() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/", ginHTML(`<html>
+	<body></body>
+	<script>
+		(async () => {
+			const a = await (await fetch('/a')).text()
+			const b = await (await fetch('/a')).text()
+			document.body.innerText = a + b
+		})()
+	</script></html>`))
+	engine.GET("/a", ginString("ok"))
+
+	page := s.browser.Page("")
+	defer page.Close()
+
+	router := page.HijackRequests()
+	defer router.Stop()
+
+	router.Handle(url+"/a", &rod.HijackOptions{ID: "default"}, func(ctx *rod.Hijack) {
+		ctx.LoadResponse()
+	})
+	router.Handle(url+"/a", &rod.HijackOptions{Priority: 1, Once: true}, func(ctx *rod.Hijack) {
+		ctx.Response.SetBody("once")
+	})
+	go router.Run()
+
+	page.Navigate(url)
+	s.Equal("onceok", page.Element("body").Text())
+
+	router.RemoveID("default")
+}
+
 func (s *S) TestHandleAuth() {
 	url, engine, close := serve()
 	defer close()
@@ -172,6 +354,38 @@ func (s *S) TestHandleAuth() {
 	page.ElementMatches("p", "ok")
 }
 
+func (s *S) TestHandleAuthWith() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.NoRoute(func(ctx kit.GinContext) {
+		u, p, ok := ctx.Request.BasicAuth()
+		if !ok || u != "a" || p != "b" {
+			ctx.Header("WWW-Authenticate", `Basic realm="web"`)
+			ctx.Writer.WriteHeader(401)
+			return
+		}
+		ginHTML(`<p>ok</p>`)(ctx)
+	})
+
+	page := s.browser.Page("")
+	defer page.Close()
+
+	router := page.HijackRequests()
+	defer router.Stop()
+
+	router.Add(url+"/mock", func(ctx *rod.Hijack) {
+		ctx.Response.SetBody("<p>mock</p>")
+	})
+	router.HandleAuth(rod.NewAuthStore().Set(url, "web", "a", "b").Provide)
+	go router.Run()
+
+	// every challenge is answered
+	page.Navigate(url+"/a").ElementMatches("p", "ok")
+	page.Navigate(url+"/b").ElementMatches("p", "ok")
+	page.Navigate(url+"/mock").ElementMatches("p", "mock")
+}
+
 func (s *S) TestGetDownloadFile() {
 	url, engine, close := serve()
 	defer close()
@@ -85,7 +85,10 @@ func getDeviceList() string {
 }
 
 func genHelperList(helper string) string {
-	m := regexp.MustCompile(`\},?\n\n {4}(?:async )?([a-z][^ ]+) \(`).FindAllStringSubmatch(helper, -1)
+	// only the methods of the rod object are public, the functions after it are private
+	rod := regexp.MustCompile(`(?s)const rod = \{\n(.*?)\n  \}\n`).FindStringSubmatch(helper)[1]
+
+	m := regexp.MustCompile(`\},?\n\n {4}(?:async )?([a-z][^ ]+) \(`).FindAllStringSubmatch(rod, -1)
 	list := "package js\n\n" +
 		"// NameType type\n" +
 		"type NameType string\n\n" +
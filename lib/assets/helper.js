@@ -5,7 +5,7 @@
     element (...selectors) {
       const scope = ensureScope(this)
       for (const selector of selectors) {
-        const el = scope.querySelector(selector)
+        const el = querySelector(scope, selector)
         if (el) {
           return el
         }
@@ -14,7 +14,7 @@
     },
 
     elements (selector) {
-      return ensureScope(this).querySelectorAll(selector)
+      return querySelectorAll(ensureScope(this), selector)
     },
 
     elementX (...xPaths) {
@@ -44,7 +44,7 @@
         const selector = pairs[i]
         const pattern = pairs[i + 1]
         const reg = new RegExp(pattern)
-        const el = Array.from((this.document || this).querySelectorAll(selector)).find(
+        const el = querySelectorAll(ensureScope(this), selector).find(
           e => reg.test(rod.text.call(e))
         )
         if (el) {
@@ -54,6 +54,30 @@
       return null
     },
 
+    locate (chain) {
+      let scopes = [ensureScope(this)]
+      for (let i = 0; i < chain.length; i++) {
+        const { selector, text, nth } = chain[i]
+        const reg = text && new RegExp(text)
+        let list = []
+        for (const scope of scopes) {
+          for (const el of rod.elements.call(scope, selector)) {
+            if (!list.includes(el) && (!reg || reg.test(rod.text.call(el)))) {
+              list.push(el)
+            }
+          }
+        }
+        if (nth != null) {
+          list = nth < list.length ? [list[nth]] : []
+        }
+        if (list.length === 0) {
+          return i
+        }
+        scopes = list
+      }
+      return scopes[0]
+    },
+
     parents (selector) {
       let p = this.parentElement
       const list = []
@@ -241,6 +265,10 @@
       return !rod.visible.apply(this)
     },
 
+    enabled () {
+      return !ensureElement(this).matches(':disabled')
+    },
+
     text () {
       switch (this.tagName) {
         case 'INPUT':
@@ -255,6 +283,81 @@
       }
     },
 
+    selectorPath () {
+      // the css selector path of the element, the path in a shadow root is joined to its host's with ">>"
+      const path = []
+      let el = this
+      while (el) {
+        const root = el.getRootNode()
+        if (el.id && root.querySelectorAll('#' + CSS.escape(el.id)).length === 1) {
+          path.unshift('#' + CSS.escape(el.id))
+          break
+        }
+
+        let selector = el.tagName.toLowerCase()
+        const parent = el.parentElement
+        if (parent) {
+          const same = Array.from(parent.children).filter(e => e.tagName === el.tagName)
+          if (same.length > 1) {
+            selector += `:nth-of-type(${same.indexOf(el) + 1})`
+          }
+        }
+        path.unshift(selector)
+        el = parent
+      }
+
+      const host = this.getRootNode().host
+      const str = path.join(' > ')
+      return host ? rod.selectorPath.call(host) + ' >> ' + str : str
+    },
+
+    contrast () {
+      // the contrast ratio of the text color to the background, and the minimum ratio required by the WCAG AA
+      const style = window.getComputedStyle(this)
+      const bg = backgroundColor(this)
+      const fg = blendColor(parseColor(style.color), bg)
+      const size = parseFloat(style.fontSize)
+      const large = size >= 24 || (parseInt(style.fontWeight) >= 700 && size >= 18.66)
+
+      const [l1, l2] = [luminance(fg), luminance(bg)].sort((a, b) => b - a)
+      return {
+        ratio: Math.round((l1 + 0.05) / (l2 + 0.05) * 100) / 100,
+        required: large ? 3 : 4.5,
+        color: formatColor(fg),
+        background: formatColor(bg)
+      }
+    },
+
+    lowContrast () {
+      // the visible elements that have their own text with insufficient contrast
+      return deepElements(document).filter(el => {
+        const hasText = Array.from(el.childNodes).some(n => n.nodeType === Node.TEXT_NODE && n.textContent.trim())
+        if (!hasText || !rod.visible.call(el)) {
+          return false
+        }
+        const c = rod.contrast.call(el)
+        return c.ratio < c.required
+      })
+    },
+
+    duplicateIDs () {
+      // the elements that share their ids with others in the same document or shadow root
+      const scopes = [document].concat(deepElements(document).filter(el => el.shadowRoot).map(el => el.shadowRoot))
+      const list = []
+      for (const scope of scopes) {
+        const groups = {}
+        for (const el of scope.querySelectorAll('[id]')) {
+          (groups[el.id] = groups[el.id] || []).push(el)
+        }
+        for (const id in groups) {
+          if (groups[id].length > 1) {
+            list.push(...groups[id])
+          }
+        }
+      }
+      return list
+    },
+
     resource () {
       return new Promise((resolve, reject) => {
         if (this.complete) {
@@ -322,6 +425,331 @@
     }
   }
 
+  // The selector engines are addressable by the prefix of the selector, such as "text=Save".
+  // Except the css, they pierce the open shadow roots.
+  const selectorEngines = {
+    css: queryCSS,
+    text: queryText,
+    role: queryRole,
+    label: queryLabel,
+    placeholder: queryPlaceholder,
+    testid: queryTestID
+  }
+
+  function parseSelector (selector) {
+    const m = selector.match(/^([a-z]+)=([\s\S]*)$/)
+    if (m && selectorEngines[m[1]]) {
+      return { engine: selectorEngines[m[1]], body: m[2] }
+    }
+    return { engine: queryCSS, body: selector }
+  }
+
+  // the css selector uses the native querySelector, so it won't collect all the matches
+  function querySelector (scope, selector) {
+    const { engine, body } = parseSelector(selector)
+    if (engine === queryCSS) {
+      return scope.querySelector(body)
+    }
+    return engine(scope, body)[0] || null
+  }
+
+  function querySelectorAll (scope, selector) {
+    const { engine, body } = parseSelector(selector)
+    return engine(scope, body)
+  }
+
+  function queryCSS (scope, selector) {
+    return Array.from(scope.querySelectorAll(selector))
+  }
+
+  // the innermost elements that have the text, for the exact match wrap the text with quotes
+  function queryText (scope, text) {
+    const match = textMatcher(text)
+    const skip = ['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE', 'HEAD']
+    const list = []
+
+    // returns true if the el or one of its descendants matches
+    const visit = (el) => {
+      if (skip.includes(el.tagName)) {
+        return false
+      }
+      let matched = false
+      for (const child of childElements(el)) {
+        matched = visit(child) || matched
+      }
+      if (matched) {
+        return true
+      }
+      if (match(elementText(el))) {
+        list.push(el)
+        return true
+      }
+      return false
+    }
+
+    childElements(scope).forEach(visit)
+    return list
+  }
+
+  // such as: button[name="Save"], checkbox[checked=true], heading[level=2]
+  function queryRole (scope, selector) {
+    const role = (selector.match(/^\s*([a-z]+)/i) || [])[1]
+    const attrReg = /\[\s*([a-z-]+)\s*(?:=\s*("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|\/(?:[^/\\]|\\.)*\/[a-z]*|[^\]]*?))?\s*\]/gi
+    const filters = []
+    let m
+    while ((m = attrReg.exec(selector))) {
+      const [, name, value = 'true'] = m
+      if (name === 'name') {
+        const match = textMatcher(value)
+        filters.push(el => match(accessibleName(el)))
+      } else {
+        filters.push(el => String(ariaState(el, name)) === value.replace(/^["']|["']$/g, ''))
+      }
+    }
+
+    return deepElements(scope).filter(el =>
+      ariaRole(el) === role && filters.every(f => f(el))
+    )
+  }
+
+  function queryLabel (scope, text) {
+    const match = textMatcher(text)
+    return deepElements(scope).filter(el => {
+      const labels = el.labels ? Array.from(el.labels).map(l => l.textContent) : []
+      labels.push(el.getAttribute('aria-label'), labelledBy(el))
+      return labels.some(l => l && match(l))
+    })
+  }
+
+  function queryPlaceholder (scope, text) {
+    const match = textMatcher(text)
+    return deepElements(scope).filter(el => el.hasAttribute('placeholder') && match(el.getAttribute('placeholder')))
+  }
+
+  function queryTestID (scope, id) {
+    id = id.trim().replace(/^["']|["']$/g, '')
+    return deepElements(scope).filter(el => el.getAttribute('data-testid') === id)
+  }
+
+  // The text will be matched case-insensitively as a substring, wrap it with quotes for the exact match,
+  // or use the /regex/flags
+  function textMatcher (text) {
+    text = text.trim()
+
+    const reg = text.match(/^\/([\s\S]*)\/([a-z]*)$/)
+    if (reg) {
+      const r = new RegExp(reg[1], reg[2])
+      return s => r.test(normalizeText(s))
+    }
+
+    const quoted = text.match(/^"([\s\S]*)"$/) || text.match(/^'([\s\S]*)'$/)
+    if (quoted) {
+      const str = normalizeText(quoted[1])
+      return s => normalizeText(s) === str
+    }
+
+    const str = normalizeText(text).toLowerCase()
+    return s => normalizeText(s).toLowerCase().includes(str)
+  }
+
+  function normalizeText (s) {
+    return (s || '').replace(/\s+/g, ' ').trim()
+  }
+
+  function elementText (el) {
+    if (el.tagName === 'INPUT' && ['button', 'submit', 'reset'].includes(el.type)) {
+      return el.value
+    }
+    return el.textContent
+  }
+
+  // the child elements, including the ones in the open shadow root
+  function childElements (node) {
+    const list = node.shadowRoot ? Array.from(node.shadowRoot.children) : []
+    return list.concat(Array.from(node.children || []))
+  }
+
+  // all the descendant elements, including the ones in the open shadow roots
+  function deepElements (scope) {
+    const list = []
+    const walk = (node) => {
+      for (const el of childElements(node)) {
+        list.push(el)
+        walk(el)
+      }
+    }
+    walk(scope)
+    return list
+  }
+
+  const implicitRoles = {
+    article: 'article',
+    aside: 'complementary',
+    button: 'button',
+    dialog: 'dialog',
+    fieldset: 'group',
+    footer: 'contentinfo',
+    form: 'form',
+    h1: 'heading',
+    h2: 'heading',
+    h3: 'heading',
+    h4: 'heading',
+    h5: 'heading',
+    h6: 'heading',
+    header: 'banner',
+    hr: 'separator',
+    li: 'listitem',
+    main: 'main',
+    nav: 'navigation',
+    ol: 'list',
+    option: 'option',
+    progress: 'progressbar',
+    section: 'region',
+    table: 'table',
+    td: 'cell',
+    textarea: 'textbox',
+    th: 'columnheader',
+    tr: 'row',
+    ul: 'list'
+  }
+
+  const inputRoles = {
+    button: 'button',
+    checkbox: 'checkbox',
+    email: 'textbox',
+    image: 'button',
+    number: 'spinbutton',
+    radio: 'radio',
+    range: 'slider',
+    reset: 'button',
+    search: 'searchbox',
+    submit: 'button',
+    tel: 'textbox',
+    text: 'textbox',
+    url: 'textbox'
+  }
+
+  // the explicit role attribute, or the implicit role of the tag
+  function ariaRole (el) {
+    const explicit = (el.getAttribute('role') || '').trim().split(/\s+/)[0]
+    if (explicit) {
+      return explicit
+    }
+
+    const tag = el.tagName.toLowerCase()
+    switch (tag) {
+      case 'a':
+      case 'area':
+        return el.hasAttribute('href') ? 'link' : ''
+      case 'input':
+        return inputRoles[(el.getAttribute('type') || 'text').toLowerCase()] || ''
+      case 'select':
+        return el.multiple || el.size > 1 ? 'listbox' : 'combobox'
+      case 'img':
+        return el.getAttribute('alt') === '' ? 'presentation' : 'img'
+    }
+    return implicitRoles[tag] || ''
+  }
+
+  const nameFromContent = [
+    'button', 'cell', 'checkbox', 'columnheader', 'heading', 'link', 'menuitem',
+    'option', 'radio', 'rowheader', 'switch', 'tab', 'tooltip', 'treeitem'
+  ]
+
+  // a simplified version of the accessible name computation
+  function accessibleName (el) {
+    const label = el.getAttribute('aria-label')
+    let name
+    if (labelledBy(el)) {
+      name = labelledBy(el)
+    } else if (label && label.trim()) {
+      name = label
+    } else if (el.labels && el.labels.length) {
+      name = Array.from(el.labels).map(l => l.textContent).join(' ')
+    } else if (el.tagName === 'IMG' || (el.tagName === 'INPUT' && el.type === 'image')) {
+      name = el.getAttribute('alt') || el.title
+    } else if (el.tagName === 'INPUT' && ['button', 'submit', 'reset'].includes(el.type)) {
+      name = el.value
+    } else if (nameFromContent.includes(ariaRole(el))) {
+      name = el.textContent
+    } else {
+      name = el.title || el.getAttribute('placeholder')
+    }
+    return normalizeText(name)
+  }
+
+  function labelledBy (el) {
+    const ids = el.getAttribute('aria-labelledby')
+    if (!ids) {
+      return ''
+    }
+    const root = el.getRootNode()
+    return ids.trim().split(/\s+/).map(id => {
+      const label = root.getElementById ? root.getElementById(id) : document.getElementById(id)
+      return label ? label.textContent : ''
+    }).join(' ')
+  }
+
+  function ariaState (el, name) {
+    const aria = el.getAttribute('aria-' + name)
+    switch (name) {
+      case 'checked':
+        return aria || ('checked' in el ? el.checked : false)
+      case 'disabled':
+        return aria || el.matches(':disabled')
+      case 'selected':
+        return aria || ('selected' in el ? el.selected : false)
+      case 'level': {
+        const m = el.tagName.match(/^H([1-6])$/)
+        return aria || (m ? m[1] : '')
+      }
+    }
+    return aria || false
+  }
+
+  // the color is [r, g, b, a], the r, g, b are 0-255 and the a is 0-1
+  function parseColor (str) {
+    const m = str.match(/rgba?\(([^)]+)\)/)
+    if (!m) {
+      return [0, 0, 0, 1]
+    }
+    const [r, g, b, a = 1] = m[1].split(/[\s,/]+/).filter(v => v).map(parseFloat)
+    return [r, g, b, a]
+  }
+
+  function formatColor ([r, g, b]) {
+    return `rgb(${Math.round(r)}, ${Math.round(g)}, ${Math.round(b)})`
+  }
+
+  // blend the color over the opaque background
+  function blendColor ([r, g, b, a], [br, bg, bb]) {
+    return [r * a + br * (1 - a), g * a + bg * (1 - a), b * a + bb * (1 - a), 1]
+  }
+
+  // the opaque color that the background of the element looks like, the page background is white
+  function backgroundColor (el) {
+    const layers = []
+    for (let node = el; node; node = node.parentElement || (node.getRootNode() || {}).host) {
+      const color = parseColor(window.getComputedStyle(node).backgroundColor)
+      if (color[3] > 0) {
+        layers.push(color)
+      }
+      if (color[3] === 1) {
+        break
+      }
+    }
+    return layers.reduceRight((bg, color) => blendColor(color, bg), [255, 255, 255, 1])
+  }
+
+  // the relative luminance defined by the WCAG
+  function luminance ([r, g, b]) {
+    const [lr, lg, lb] = [r, g, b].map(v => {
+      v /= 255
+      return v <= 0.03928 ? v / 12.92 : Math.pow((v + 0.055) / 1.055, 2.4)
+    })
+    return 0.2126 * lr + 0.7152 * lg + 0.0722 * lb
+  }
+
   function ensureScope (s) {
     return s === window ? s.document : s
   }
@@ -14,6 +14,8 @@ const (
 	ElementsX NameType = "elementsX"
 	//ElementMatches NameType function name
 	ElementMatches NameType = "elementMatches"
+	//Locate NameType function name
+	Locate NameType = "locate"
 	//Parents NameType function name
 	Parents NameType = "parents"
 	//ContainsElement NameType function name
@@ -44,8 +46,18 @@ const (
 	Visible NameType = "visible"
 	//Invisible NameType function name
 	Invisible NameType = "invisible"
+	//Enabled NameType function name
+	Enabled NameType = "enabled"
 	//Text NameType function name
 	Text NameType = "text"
+	//SelectorPath NameType function name
+	SelectorPath NameType = "selectorPath"
+	//Contrast NameType function name
+	Contrast NameType = "contrast"
+	//LowContrast NameType function name
+	LowContrast NameType = "lowContrast"
+	//DuplicateIDs NameType function name
+	DuplicateIDs NameType = "duplicateIDs"
 	//Resource NameType function name
 	Resource NameType = "resource"
 	//AddScriptTag NameType function name
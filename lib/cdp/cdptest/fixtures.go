@@ -0,0 +1,41 @@
+package cdptest
+
+import (
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// Object returns a handler that responds the remote object of the id as the result,
+// such as for the "Runtime.evaluate" that returns the window object
+func Object(objectID proto.RuntimeRemoteObjectID) Handler {
+	return Result(map[string]interface{}{
+		"result": &proto.RuntimeRemoteObject{
+			Type:     proto.RuntimeRemoteObjectTypeObject,
+			ObjectID: objectID,
+			Value:    proto.NewJSON(nil),
+		},
+	})
+}
+
+// Value returns a handler that responds the value as the result that is returned by value,
+// such as for the "Runtime.callFunctionOn"
+func Value(value interface{}) Handler {
+	return Result(map[string]interface{}{
+		"result": &proto.RuntimeRemoteObject{
+			Value: proto.NewJSON(value),
+		},
+	})
+}
+
+// AXValue of the accessibility node, the type is inferred from the value
+func AXValue(value interface{}) *proto.AccessibilityAXValue {
+	t := proto.AccessibilityAXValueTypeString
+	switch value.(type) {
+	case bool:
+		t = proto.AccessibilityAXValueTypeBoolean
+	case int, int64:
+		t = proto.AccessibilityAXValueTypeInteger
+	case float64:
+		t = proto.AccessibilityAXValueTypeNumber
+	}
+	return &proto.AccessibilityAXValue{Type: t, Value: proto.NewJSON(value)}
+}
@@ -0,0 +1,184 @@
+// Package cdptest provides a scriptable fake browser that speaks the devtools protocol,
+// so that the code built on cdp.Client can be tested without launching a real browser.
+package cdptest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/tidwall/gjson"
+	"github.com/ysmood/kit"
+)
+
+// Call is a request received by the fake browser
+type Call struct {
+	ID        uint64          `json:"id"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method"`
+	Params    json.RawMessage `json:"params,omitempty"`
+}
+
+// JSONParams of the call
+func (c *Call) JSONParams() gjson.Result {
+	return gjson.ParseBytes(c.Params)
+}
+
+// Handler returns the result of the call. If the error is a *cdp.Error it will be sent to
+// the client as is, any other error will be sent as a cdp.Error with code -32000.
+type Handler func(call *Call) (result interface{}, err error)
+
+// Result returns a handler that always responds the result
+func Result(result interface{}) Handler {
+	return func(*Call) (interface{}, error) {
+		return result, nil
+	}
+}
+
+// Fail returns a handler that always responds the cdp error
+func Fail(code int64, message string) Handler {
+	return func(*Call) (interface{}, error) {
+		return nil, &cdp.Error{Code: code, Message: message}
+	}
+}
+
+// Browser is the fake browser, it implements the cdp.Websocketable interface.
+// By default every call will get an empty object as the result, use Handle to customize it.
+// The handlers are called in the goroutine that sends the requests, they should not block.
+type Browser struct {
+	lock     *sync.Mutex
+	handlers map[string]Handler
+	fallback Handler
+	calls    []*Call
+
+	queue *cdp.MessageQueue // the messages waiting to be read by the client
+}
+
+var _ cdp.Websocketable = &Browser{}
+
+// New fake browser
+func New() *Browser {
+	return &Browser{
+		lock:     &sync.Mutex{},
+		handlers: map[string]Handler{},
+		fallback: Result(map[string]interface{}{}),
+		queue:    cdp.NewMessageQueue(),
+	}
+}
+
+// Client returns a cdp client that is connected to the fake browser
+func (b *Browser) Client() *cdp.Client {
+	return cdp.New("").Websocket(b)
+}
+
+// Handle sets the handler for the method, such as "Target.createTarget".
+// Set the handler to nil to remove it.
+func (b *Browser) Handle(method string, handler Handler) *Browser {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if handler == nil {
+		delete(b.handlers, method)
+	} else {
+		b.handlers[method] = handler
+	}
+	return b
+}
+
+// Fallback sets the handler for the methods that have no handler.
+// Such as use Fail(-32601, "method not found") to make the fake browser strict.
+func (b *Browser) Fallback(handler Handler) *Browser {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.fallback = handler
+	return b
+}
+
+// Emit an event to the client, the sessionID can be empty
+func (b *Browser) Emit(sessionID, method string, params interface{}) {
+	b.queue.Push(kit.MustToJSONBytes(&cdp.Event{
+		SessionID: sessionID,
+		Method:    method,
+		Params:    kit.MustToJSONBytes(params),
+	}))
+}
+
+// Calls returns the received calls of the method, if method is empty all calls will be returned
+func (b *Browser) Calls(method string) []*Call {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	list := []*Call{}
+	for _, c := range b.calls {
+		if method == "" || c.Method == method {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// Called returns how many times the method is called
+func (b *Browser) Called(method string) int {
+	return len(b.Calls(method))
+}
+
+// Reset the received calls
+func (b *Browser) Reset() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.calls = nil
+}
+
+// Connect interface, the url and header are ignored
+func (b *Browser) Connect(ctx context.Context, _ string, _ http.Header) (cdp.WebsocketableConn, error) {
+	return &conn{ctx, b}, nil
+}
+
+func (b *Browser) handle(data []byte) error {
+	var call Call
+	err := json.Unmarshal(data, &call)
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	b.calls = append(b.calls, &call)
+	handler, has := b.handlers[call.Method]
+	if !has {
+		handler = b.fallback
+	}
+	b.lock.Unlock()
+
+	res := map[string]interface{}{"id": call.ID}
+	result, err := handler(&call)
+	if err != nil {
+		cdpErr, ok := err.(*cdp.Error)
+		if !ok {
+			cdpErr = &cdp.Error{Code: -32000, Message: err.Error()}
+		}
+		res["error"] = cdpErr
+	} else {
+		if result == nil {
+			result = map[string]interface{}{}
+		}
+		res["result"] = result
+	}
+
+	b.queue.Push(kit.MustToJSONBytes(res))
+	return nil
+}
+
+type conn struct {
+	ctx     context.Context
+	browser *Browser
+}
+
+func (c *conn) Send(data []byte) error {
+	return c.browser.handle(data)
+}
+
+func (c *conn) Read() ([]byte, error) {
+	return c.browser.queue.Read(c.ctx)
+}
@@ -0,0 +1,101 @@
+package cdptest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestBasic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fake := cdptest.New().
+		Handle("Browser.getVersion", cdptest.Result(map[string]string{"product": "fake"})).
+		Handle("Target.attachToTarget", cdptest.Fail(-32000, "No target with given id found")).
+		Handle("Runtime.evaluate", func(call *cdptest.Call) (interface{}, error) {
+			return nil, errors.New(call.JSONParams().Get("expression").String())
+		})
+
+	client := fake.Client().Context(ctx, cancel).Connect()
+
+	res, err := client.Call(ctx, "", "Browser.getVersion", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"product":"fake"}`, string(res))
+
+	_, err = client.Call(ctx, "", "Target.attachToTarget", nil)
+	assert.Equal(t, &cdp.Error{Code: -32000, Message: "No target with given id found"}, err)
+
+	_, err = client.Call(ctx, "s", "Runtime.evaluate", map[string]string{"expression": "err"})
+	assert.Equal(t, "err", err.(*cdp.Error).Message)
+
+	res, err = client.Call(ctx, "", "Page.enable", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, `{}`, string(res))
+
+	fake.Emit("s", "Page.loadEventFired", map[string]int{"timestamp": 1})
+	e := <-client.Event()
+	assert.Equal(t, "s", e.SessionID)
+	assert.Equal(t, "Page.loadEventFired", e.Method)
+	assert.Equal(t, `{"timestamp":1}`, string(e.Params))
+
+	assert.Equal(t, 1, fake.Called("Runtime.evaluate"))
+	assert.Equal(t, "s", fake.Calls("Runtime.evaluate")[0].SessionID)
+	assert.Len(t, fake.Calls(""), 4)
+
+	fake.Reset()
+	fake.Handle("Browser.getVersion", nil).Fallback(cdptest.Fail(-32601, "method not found"))
+	_, err = client.Call(ctx, "", "Browser.getVersion", nil)
+	assert.EqualValues(t, -32601, err.(*cdp.Error).Code)
+	assert.Len(t, fake.Calls(""), 1)
+}
+
+func TestRodNavigationErr(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fake := cdptest.New().Handle("Page.navigate", cdptest.Result(&proto.PageNavigateResult{
+		ErrorText: "net::ERR_NAME_NOT_RESOLVED",
+	}))
+
+	page := rod.New().Context(ctx, cancel).Client(fake.Client()).Connect().Page("")
+
+	err := page.NavigateE("http://not-exists")
+	assert.True(t, errors.Is(err, rod.ErrNavigation))
+	assert.False(t, errors.Is(err, rod.ErrNavigationAborted))
+	assert.Equal(t, "http://not-exists", fake.Calls("Page.navigate")[0].JSONParams().Get("url").String())
+
+	fake.Handle("Page.navigate", cdptest.Result(&proto.PageNavigateResult{ErrorText: "net::ERR_ABORTED"}))
+	err = page.NavigateE("http://aborted")
+	assert.True(t, errors.Is(err, rod.ErrNavigationAborted))
+	assert.True(t, errors.Is(err, rod.ErrNavigation))
+
+	fake.Handle("Runtime.evaluate", cdptest.Fail(-32000, "Execution context was destroyed."))
+	_, err = proto.RuntimeEvaluate{Expression: "1"}.Call(page)
+	assert.True(t, errors.Is(err, rod.ErrContextDestroyed))
+}
+
+func TestFixtures(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fake := cdptest.New().
+		Handle("Runtime.evaluate", cdptest.Object("window")).
+		Handle("Runtime.callFunctionOn", cdptest.Value(map[string]int{"a": 1}))
+	client := fake.Client().Context(ctx, cancel).Connect()
+
+	res, err := client.Call(ctx, "", "Runtime.evaluate", nil)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"result":{"type":"object","objectId":"window","value":null}}`, string(res))
+
+	res, err = client.Call(ctx, "", "Runtime.callFunctionOn", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":1}`, gjson.GetBytes(res, "result.value").Raw)
+}
@@ -0,0 +1,69 @@
+package cdp
+
+import (
+	"errors"
+	"strings"
+)
+
+// The classification of the errors from the browser, use them with errors.Is, such as:
+//
+//	errors.Is(err, cdp.ErrObjectNotFound)
+//
+// Some of them are transient, such as ErrContextDestroyed, retrying after the page is stable usually helps.
+var (
+	// ErrTargetClosed the target is closed or doesn't exist
+	ErrTargetClosed = errors.New("[cdp] target closed")
+	// ErrSessionNotFound the session is detached or doesn't exist
+	ErrSessionNotFound = errors.New("[cdp] session not found")
+	// ErrObjectNotFound the remote object or dom node is released or doesn't exist, such as a stale node after navigation
+	ErrObjectNotFound = errors.New("[cdp] object not found")
+	// ErrContextDestroyed the javascript execution context is destroyed, such as the page is navigating
+	ErrContextDestroyed = errors.New("[cdp] execution context destroyed")
+)
+
+// known error messages from the browser, they are matched by substring
+var errClassifications = []struct {
+	code     int64 // 0 means any code
+	messages []string
+	err      error
+}{
+	{-32001, []string{"Session with given id not found"}, ErrSessionNotFound},
+	{0, []string{
+		"Target closed",
+		"No target with given id found",
+		"Inspected target navigated or closed",
+	}, ErrTargetClosed},
+	{-32000, []string{
+		"Could not find object with given id",
+		"Could not find node with given id",
+		"No node with given id found",
+		"Node with given id does not belong to the document",
+		"No node found for given backend id",
+	}, ErrObjectNotFound},
+	{-32000, []string{
+		"Cannot find context with specified id",
+		"Execution context was destroyed",
+		"Cannot find default execution context",
+	}, ErrContextDestroyed},
+}
+
+// Is interface, it classifies the error via its code and message
+func (e *Error) Is(target error) bool {
+	kind := e.Kind()
+	return kind != nil && kind == target
+}
+
+// Kind returns the classification sentinel of the error, nil if it's unknown
+func (e *Error) Kind() error {
+	for _, c := range errClassifications {
+		if c.code != 0 && c.code != e.Code {
+			continue
+		}
+		for _, msg := range c.messages {
+			if strings.Contains(e.Message, msg) {
+				return c.err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package cdp
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what to do when the queue of an event subscriber is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the subscriber receives the event, a slow subscriber will stall the client
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropOldest discards the oldest queued event to make room for the new one
+	OverflowDropOldest
+
+	// OverflowDropNewest discards the new event
+	OverflowDropNewest
+)
+
+// Subscription is a bounded event queue of a subscriber
+type Subscription struct {
+	ctx     context.Context
+	ch      chan *Event
+	policy  OverflowPolicy
+	dropped uint64
+}
+
+// Event returns the channel of the queue, it will be closed when the subscription is canceled
+func (s *Subscription) Event() <-chan *Event {
+	return s.ch
+}
+
+// Dropped returns how many events are discarded because of the overflow policy
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// events holds all the subscriptions of a client
+type events struct {
+	lock    sync.Mutex
+	main    *Subscription // the one for Client.Event
+	list    []*Subscription
+	dropped uint64
+}
+
+// EventBuffer sets the queue size and overflow policy of the channel returned by Client.Event.
+// By default the size is 0 and the policy is OverflowBlock. It should be called before Connect.
+func (cdp *Client) EventBuffer(size int, policy OverflowPolicy) *Client {
+	cdp.events.main = newSubscription(context.Background(), size, policy)
+	return cdp
+}
+
+// Subscribe creates an event queue that receives all the events from the browser.
+// When the ctx is done the subscription will be removed and its channel will be closed.
+func (cdp *Client) Subscribe(ctx context.Context, size int, policy OverflowPolicy) *Subscription {
+	s := newSubscription(ctx, size, policy)
+
+	cdp.events.lock.Lock()
+	cdp.events.list = append(cdp.events.list, s)
+	cdp.events.lock.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-cdp.ctx.Done():
+		}
+
+		cdp.events.lock.Lock()
+		defer cdp.events.lock.Unlock()
+		for i, item := range cdp.events.list {
+			if item == s {
+				cdp.events.list = append(cdp.events.list[:i], cdp.events.list[i+1:]...)
+				break
+			}
+		}
+		close(s.ch)
+	}()
+
+	return s
+}
+
+// DroppedEvents returns the total number of events discarded by all the subscriptions of the client
+func (cdp *Client) DroppedEvents() uint64 {
+	return atomic.LoadUint64(&cdp.events.dropped)
+}
+
+func newSubscription(ctx context.Context, size int, policy OverflowPolicy) *Subscription {
+	if size < 0 {
+		size = 0
+	}
+	return &Subscription{
+		ctx:    ctx,
+		ch:     make(chan *Event, size),
+		policy: policy,
+	}
+}
+
+// dispatch the event to every subscription, returns false if the client is closed
+func (cdp *Client) dispatch(e *Event) bool {
+	if !cdp.deliver(cdp.events.main, e) {
+		return false
+	}
+
+	cdp.events.lock.Lock()
+	defer cdp.events.lock.Unlock()
+
+	for _, s := range cdp.events.list {
+		if !cdp.deliver(s, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (cdp *Client) deliver(s *Subscription, e *Event) bool {
+	if s.policy == OverflowBlock {
+		select {
+		case <-cdp.ctx.Done():
+			return false
+		case <-s.ctx.Done():
+		case s.ch <- e:
+		}
+		return true
+	}
+
+	for {
+		select {
+		case s.ch <- e:
+			return true
+		default:
+		}
+
+		if s.policy == OverflowDropOldest && cap(s.ch) > 0 {
+			select {
+			case <-s.ch:
+				cdp.drop(s)
+			default: // the subscriber has just received one
+			}
+			continue
+		}
+
+		cdp.drop(s)
+		return true
+	}
+}
+
+func (cdp *Client) drop(s *Subscription) {
+	atomic.AddUint64(&s.dropped, 1)
+	atomic.AddUint64(&cdp.events.dropped, 1)
+}
@@ -24,12 +24,17 @@ type Client struct {
 
 	callbacks *sync.Map // buffer for response from browser
 
-	chReq   chan []byte    // request from user
-	chRes   chan *response // response from browser
-	chEvent chan *Event    // events from browser
+	chReq    chan *requestMsg // request from user
+	chRes    chan *response   // response from browser
+	chBroken chan *brokenMsg  // the error that breaks the connection
+
+	events *events // the subscriptions of the events from browser
 
 	count uint64
 
+	reconnect   *ReconnectPolicy
+	onReconnect []func()
+
 	debug    bool
 	debugLog func(interface{})
 }
@@ -77,6 +82,7 @@ func (e *Error) Error() string {
 }
 
 // New creates a cdp connection, all messages from Client.Event must be received or they will block the client.
+// Use Client.EventBuffer or Client.Subscribe with a drop policy to prevent a slow subscriber from stalling the client.
 func New(websocketURL string) *Client {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -84,9 +90,10 @@ func New(websocketURL string) *Client {
 		ctx:       ctx,
 		ctxCancel: cancel,
 		callbacks: &sync.Map{},
-		chReq:     make(chan []byte),
+		chReq:     make(chan *requestMsg),
 		chRes:     make(chan *response),
-		chEvent:   make(chan *Event),
+		events:    &events{main: newSubscription(context.Background(), 0, OverflowBlock)},
+		chBroken:  make(chan *brokenMsg),
 		wsURL:     websocketURL,
 		debug:     defaults.CDP,
 	}
@@ -167,7 +174,8 @@ func (cdp *Client) Call(ctx context.Context, sessionID, method string, params in
 	data, err := json.Marshal(req)
 	utils.E(err)
 
-	callback := make(chan *response)
+	// buffered, so that a connection lost notice will never block the consumer
+	callback := make(chan *response, 1)
 
 	cdp.callbacks.Store(req.ID, callback)
 	defer cdp.callbacks.Delete(req.ID)
@@ -178,7 +186,7 @@ func (cdp *Client) Call(ctx context.Context, sessionID, method string, params in
 
 	case <-ctx.Done():
 		return nil, ctx.Err()
-	case cdp.chReq <- data:
+	case cdp.chReq <- &requestMsg{req, data}:
 	}
 
 	select {
@@ -189,6 +197,9 @@ func (cdp *Client) Call(ctx context.Context, sessionID, method string, params in
 		return nil, ctx.Err()
 
 	case res := <-callback:
+		if res.err != nil {
+			return nil, res.err
+		}
 		if res.Error != nil {
 			return nil, res.Error
 		}
@@ -197,9 +208,10 @@ func (cdp *Client) Call(ctx context.Context, sessionID, method string, params in
 
 }
 
-// Event returns a channel that will emit browser devtools protocol events. Must be consumed or will block producer.
+// Event returns a channel that will emit browser devtools protocol events.
+// Must be consumed or will block producer, unless the overflow policy of Client.EventBuffer says otherwise.
 func (cdp *Client) Event() <-chan *Event {
-	return cdp.chEvent
+	return cdp.events.main.ch
 }
 
 type requestMsg struct {
@@ -209,19 +221,39 @@ type requestMsg struct {
 
 // consume messages from client and browser
 func (cdp *Client) consumeMsg() {
+	// the requests that are sent but haven't got the response yet
+	inflight := map[uint64]kit.Nil{}
+
 	for {
 		select {
 		case <-cdp.ctx.Done():
 			return
 
-		case data := <-cdp.chReq:
-			err := cdp.wsConn.Send(data)
+		case msg := <-cdp.chReq:
+			inflight[msg.request.ID] = kit.Nil{}
+			err := cdp.wsConn.Send(msg.data)
 			if err != nil {
-				cdp.close(err)
+				if cdp.reconnect == nil {
+					cdp.close(err)
+					return
+				}
+				if !cdp.reconnectE(err, inflight) {
+					return
+				}
+				inflight = map[uint64]kit.Nil{}
+			}
+
+		case msg := <-cdp.chBroken:
+			if msg.conn != cdp.wsConn { // the connection has already been replaced
+				continue
+			}
+			if !cdp.reconnectE(msg.err, inflight) {
 				return
 			}
+			inflight = map[uint64]kit.Nil{}
 
 		case res := <-cdp.chRes:
+			delete(inflight, res.ID)
 			callback, has := cdp.callbacks.Load(res.ID)
 			if has {
 				select {
@@ -239,13 +271,24 @@ type response struct {
 	ID     uint64          `json:"id"`
 	Result json.RawMessage `json:"result,omitempty"`
 	Error  *Error          `json:"error,omitempty"`
+
+	err error // the error that is not from the browser, such as connection lost
 }
 
 func (cdp *Client) readMsgFromBrowser() {
+	conn := cdp.wsConn
+
 	for cdp.ctx.Err() == nil {
-		data, err := cdp.wsConn.Read()
+		data, err := conn.Read()
 		if err != nil {
-			cdp.close(err)
+			if cdp.reconnect == nil {
+				cdp.close(err)
+				return
+			}
+			select {
+			case <-cdp.ctx.Done():
+			case cdp.chBroken <- &brokenMsg{conn, err}:
+			}
 			return
 		}
 
@@ -268,10 +311,8 @@ func (cdp *Client) readMsgFromBrowser() {
 			if cdp.debug {
 				cdp.debugLog(&evt)
 			}
-			select {
-			case <-cdp.ctx.Done():
+			if !cdp.dispatch(&evt) {
 				return
-			case cdp.chEvent <- &evt:
 			}
 		}
 	}
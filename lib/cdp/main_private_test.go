@@ -1,8 +1,12 @@
 package cdp
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -127,3 +131,268 @@ func TestCancelOnReadEvent(t *testing.T) {
 	_, err := cdp.Call(context.Background(), "", "", nil)
 	assert.Error(t, err)
 }
+
+type wsMock struct {
+	connect func() (WebsocketableConn, error)
+}
+
+func (ws *wsMock) Connect(context.Context, string, http.Header) (WebsocketableConn, error) {
+	return ws.connect()
+}
+
+func TestReconnect(t *testing.T) {
+	broken := make(chan kit.Nil)
+	sent := make(chan kit.Nil)
+	bad := &wsMockConn{
+		send: func([]byte) error {
+			close(sent)
+			return nil
+		},
+		read: func() ([]byte, error) {
+			<-broken
+			return nil, errors.New("broken")
+		},
+	}
+	req := make(chan []byte, 1)
+	good := &wsMockConn{
+		send: func(data []byte) error {
+			req <- data
+			return nil
+		},
+		read: func() ([]byte, error) {
+			id := kit.JSON(<-req).Get("id").Uint()
+			return kit.MustToJSONBytes(&response{ID: id, Result: []byte(`"ok"`)}), nil
+		},
+	}
+
+	conns := []WebsocketableConn{bad, nil, good}
+	ws := &wsMock{connect: func() (WebsocketableConn, error) {
+		conn := conns[0]
+		conns = conns[1:]
+		if conn == nil {
+			return nil, errors.New("dial err")
+		}
+		return conn, nil
+	}}
+
+	reconnected := make(chan kit.Nil)
+	cdp := New("").Websocket(ws).Reconnect(&ReconnectPolicy{
+		Sleeper: func() kit.Sleeper { return kit.CountSleeper(3) },
+	}).OnReconnect(func() { close(reconnected) })
+	defer cdp.ctxCancel()
+	assert.Nil(t, cdp.ConnectE())
+
+	go func() {
+		<-sent
+		close(broken)
+	}()
+
+	_, err := cdp.Call(context.Background(), "", "", nil)
+	var lost *ConnectionLostError
+	assert.True(t, errors.As(err, &lost))
+	assert.True(t, lost.Retriable())
+	assert.EqualError(t, errors.Unwrap(err), "broken")
+
+	<-reconnected
+
+	res, err := cdp.Call(context.Background(), "", "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, `"ok"`, string(res))
+}
+
+func TestReconnectMaxAttempts(t *testing.T) {
+	broken := make(chan kit.Nil)
+	sent := make(chan kit.Nil)
+	conn := &wsMockConn{
+		send: func([]byte) error {
+			close(sent)
+			return nil
+		},
+		read: func() ([]byte, error) {
+			<-broken
+			return nil, errors.New("broken")
+		},
+	}
+
+	dials := 0
+	ws := &wsMock{connect: func() (WebsocketableConn, error) {
+		dials++
+		if dials == 1 {
+			return conn, nil
+		}
+		return nil, errors.New("dial err")
+	}}
+
+	cdp := New("").Websocket(ws).Reconnect(&ReconnectPolicy{
+		Sleeper:     func() kit.Sleeper { return func(context.Context) error { return nil } },
+		MaxAttempts: 2,
+	})
+	assert.Nil(t, cdp.ConnectE())
+
+	go func() {
+		<-sent
+		close(broken)
+	}()
+
+	_, err := cdp.Call(context.Background(), "", "", nil)
+	var lost *ConnectionLostError
+	assert.True(t, errors.As(err, &lost))
+	assert.EqualError(t, errors.Unwrap(err), "broken")
+
+	<-cdp.ctx.Done()
+	assert.Equal(t, 1+2, dials)
+}
+
+func TestRecordReplay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// a fake browser that sends an event before each response
+	req := make(chan []byte, 10)
+	res := make(chan []byte, 10)
+	ws := &wsMock{connect: func() (WebsocketableConn, error) {
+		return &wsMockConn{
+			send: func(data []byte) error {
+				id := kit.JSON(data).Get("id").Uint()
+				res <- kit.MustToJSONBytes(&Event{Method: "test.event"})
+				res <- kit.MustToJSONBytes(&response{ID: id, Result: kit.MustToJSONBytes(kit.JSON(data).Get("params").Value())})
+				req <- data
+				return nil
+			},
+			read: func() ([]byte, error) { return <-res, nil },
+		}, nil
+	}}
+
+	trace := &notifyWriter{bytes.NewBuffer(nil), make(chan kit.Nil, 10)}
+	client := New("").Context(ctx, cancel).Websocket(NewRecorder(ws, trace)).Connect()
+	go func() {
+		for range client.Event() {
+		}
+	}()
+	_, err := client.Call(ctx, "", "a", map[string]int{"n": 1})
+	assert.Nil(t, err)
+	_, err = client.Call(ctx, "", "a", map[string]int{"n": 2})
+	assert.Nil(t, err)
+	for i := 0; i < 6; i++ {
+		<-trace.written
+	}
+	assert.Len(t, strings.Split(strings.TrimSpace(trace.buf.String()), "\n"), 6)
+
+	replayer, err := NewReplayer(trace.buf)
+	assert.Nil(t, err)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	replay := New("").Context(ctx, cancel).Websocket(replayer).Connect()
+	events := make(chan *Event, 10)
+	go func() {
+		for e := range replay.Event() {
+			events <- e
+		}
+	}()
+
+	data, err := replay.Call(ctx, "", "a", map[string]int{"n": 2})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"n":2}`, string(data))
+	assert.Equal(t, "test.event", (<-events).Method)
+	assert.Equal(t, "test.event", (<-events).Method)
+
+	data, err = replay.Call(ctx, "", "a", map[string]int{"n": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"n":1}`, string(data))
+
+	_, err = replay.Call(ctx, "", "a", map[string]int{"n": 1})
+	assert.Equal(t, int64(-32601), err.(*Error).Code)
+
+	_, err = NewReplayer(strings.NewReader("{"))
+	assert.Error(t, err)
+}
+
+func TestReplayTrailingEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	trace := bytes.NewBuffer(nil)
+	for _, f := range []*Frame{
+		{FrameTypeRequest, kit.MustToJSONBytes(&Request{ID: 1, Method: "Page.navigate"})},
+		{FrameTypeEvent, kit.MustToJSONBytes(&Event{Method: "Page.frameStartedLoading"})},
+		{FrameTypeResponse, kit.MustToJSONBytes(&response{ID: 1, Result: []byte("{}")})},
+		{FrameTypeEvent, kit.MustToJSONBytes(&Event{Method: "Page.loadEventFired"})},
+		{FrameTypeRequest, kit.MustToJSONBytes(&Request{ID: 2, Method: "Page.reload"})},
+		{FrameTypeResponse, kit.MustToJSONBytes(&response{ID: 2, Result: []byte("{}")})},
+		{FrameTypeEvent, kit.MustToJSONBytes(&Event{Method: "Page.frameNavigated"})},
+	} {
+		trace.Write(append(kit.MustToJSONBytes(f), '\n'))
+	}
+
+	replayer, err := NewReplayer(trace)
+	assert.Nil(t, err)
+
+	replay := New("").Context(ctx, cancel).Websocket(replayer).Connect()
+	events := make(chan *Event, 10)
+	go func() {
+		for e := range replay.Event() {
+			events <- e
+		}
+	}()
+
+	_, err = replay.Call(ctx, "", "Page.navigate", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Page.frameStartedLoading", (<-events).Method)
+	assert.Equal(t, "Page.loadEventFired", (<-events).Method)
+
+	// the events at the end of the trace
+	_, err = replay.Call(ctx, "", "Page.reload", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Page.frameNavigated", (<-events).Method)
+}
+
+// notifyWriter signals each write
+type notifyWriter struct {
+	buf     *bytes.Buffer
+	written chan kit.Nil
+}
+
+func (w *notifyWriter) Write(p []byte) (int, error) {
+	n, err := w.buf.Write(p)
+	w.written <- kit.Nil{}
+	return n, err
+}
+
+func TestEventOverflow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan []byte, 10)
+	for i := 0; i < 5; i++ {
+		events <- kit.MustToJSONBytes(&Event{Method: fmt.Sprintf("e%d", i)})
+	}
+	cdp := New("").Context(ctx, cancel).EventBuffer(2, OverflowDropNewest)
+	cdp.wsConn = &wsMockConn{read: func() ([]byte, error) { return <-events, nil }}
+
+	oldest := cdp.Subscribe(ctx, 2, OverflowDropOldest)
+	blocked := cdp.Subscribe(ctx, 5, OverflowBlock)
+	subCtx, subCancel := context.WithCancel(ctx)
+	unsub := cdp.Subscribe(subCtx, 0, OverflowBlock)
+	subCancel()
+	_, ok := <-unsub.Event()
+	assert.False(t, ok)
+
+	go cdp.readMsgFromBrowser()
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, fmt.Sprintf("e%d", i), (<-blocked.Event()).Method)
+	}
+
+	// the slow subscribers never stall the others
+	assert.Equal(t, "e0", (<-cdp.Event()).Method)
+	assert.Equal(t, "e1", (<-cdp.Event()).Method)
+	assert.Equal(t, "e3", (<-oldest.Event()).Method)
+	assert.Equal(t, "e4", (<-oldest.Event()).Method)
+	assert.Equal(t, uint64(3), oldest.Dropped())
+	assert.Equal(t, uint64(6), cdp.DroppedEvents())
+
+	cancel()
+	_, ok = <-oldest.Event()
+	assert.False(t, ok)
+}
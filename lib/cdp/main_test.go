@@ -1,7 +1,11 @@
 package cdp_test
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"path/filepath"
 	"testing"
@@ -166,3 +170,48 @@ func TestCrash(t *testing.T) {
 	})
 	assert.Regexp(t, `context canceled`, err.Error())
 }
+
+func TestPipe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cmdReader, cmdWriter := io.Pipe()
+	resReader, resWriter := io.Pipe()
+
+	// a fake browser that echoes the method as the result
+	go func() {
+		r := bufio.NewReader(cmdReader)
+		for {
+			req, err := r.ReadBytes(0)
+			if err != nil {
+				return
+			}
+			req = req[:len(req)-1]
+			res := fmt.Sprintf(`{"id":%d,"result":{"method":"%s"}}`,
+				kit.JSON(req).Get("id").Int(), kit.JSON(req).Get("method").String())
+			_, _ = resWriter.Write(append([]byte(res), 0))
+		}
+	}()
+
+	client := cdp.New("").Context(ctx, cancel).Websocket(cdp.NewPipe(resReader, cmdWriter)).Connect()
+
+	go func() {
+		for range client.Event() {
+		}
+	}()
+
+	res, err := client.Call(ctx, "", "Browser.getVersion", nil)
+	utils.E(err)
+	assert.Equal(t, "Browser.getVersion", kit.JSON(res).Get("method").String())
+}
+
+func TestErrorKind(t *testing.T) {
+	err := fmt.Errorf("wrap: %w", &cdp.Error{Code: -32000, Message: "Could not find node with given id"})
+	assert.True(t, errors.Is(err, cdp.ErrObjectNotFound))
+	assert.False(t, errors.Is(err, cdp.ErrContextDestroyed))
+
+	assert.True(t, errors.Is(&cdp.Error{Code: -32001, Message: "Session with given id not found."}, cdp.ErrSessionNotFound))
+	assert.True(t, errors.Is(&cdp.Error{Code: -32602, Message: "No target with given id found"}, cdp.ErrTargetClosed))
+	assert.True(t, errors.Is(&cdp.Error{Code: -32000, Message: "Cannot find context with specified id"}, cdp.ErrContextDestroyed))
+	assert.Nil(t, (&cdp.Error{Code: -32601, Message: "'a' wasn't found"}).Kind())
+}
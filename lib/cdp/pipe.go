@@ -0,0 +1,62 @@
+package cdp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+)
+
+// Pipe implements the Websocketable interface over a pair of pipes,
+// such as the ones created for the browser flag "--remote-debugging-pipe".
+// Each message is a JSON string ended with a NUL byte.
+type Pipe struct {
+	reader io.Reader
+	writer io.Writer
+}
+
+var _ Websocketable = &Pipe{}
+
+// NewPipe instance, the reader is where the browser writes to, the writer is where the browser reads from.
+// If they implement io.Closer, they will be closed once the context of the connection is done.
+func NewPipe(reader io.Reader, writer io.Writer) *Pipe {
+	return &Pipe{reader: reader, writer: writer}
+}
+
+// PipeConn is the connection type of the Pipe
+type PipeConn struct {
+	reader *bufio.Reader
+	writer io.Writer
+}
+
+// Connect interface, the url and header are ignored
+func (p *Pipe) Connect(ctx context.Context, _ string, _ http.Header) (WebsocketableConn, error) {
+	go func() {
+		<-ctx.Done()
+		for _, f := range []interface{}{p.reader, p.writer} {
+			if closer, ok := f.(io.Closer); ok {
+				_ = closer.Close()
+			}
+		}
+	}()
+
+	return &PipeConn{
+		reader: bufio.NewReader(p.reader),
+		writer: p.writer,
+	}, nil
+}
+
+// Send a message
+func (c *PipeConn) Send(data []byte) error {
+	_, err := c.writer.Write(append(data, 0))
+	return err
+}
+
+// Read a message
+func (c *PipeConn) Read() ([]byte, error) {
+	data, err := c.reader.ReadBytes(0)
+	if err != nil {
+		return nil, err
+	}
+	return data[:len(data)-1], nil
+}
@@ -0,0 +1,56 @@
+package cdp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ysmood/kit"
+)
+
+// MessageQueue holds the messages that a fake browser sends to the client, such as the Replayer's.
+// Push never blocks, Read blocks until there's a message.
+type MessageQueue struct {
+	lock  *sync.Mutex
+	queue [][]byte
+	wake  chan kit.Nil
+}
+
+// NewMessageQueue instance
+func NewMessageQueue() *MessageQueue {
+	return &MessageQueue{
+		lock: &sync.Mutex{},
+		wake: make(chan kit.Nil, 1),
+	}
+}
+
+// Push the messages to the end of the queue
+func (q *MessageQueue) Push(msgs ...[]byte) {
+	q.lock.Lock()
+	q.queue = append(q.queue, msgs...)
+	q.lock.Unlock()
+
+	select {
+	case q.wake <- kit.Nil{}:
+	default:
+	}
+}
+
+// Read the first message of the queue, it waits until there's one or the ctx is done
+func (q *MessageQueue) Read(ctx context.Context) ([]byte, error) {
+	for {
+		q.lock.Lock()
+		if len(q.queue) > 0 {
+			msg := q.queue[0]
+			q.queue = q.queue[1:]
+			q.lock.Unlock()
+			return msg, nil
+		}
+		q.lock.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-q.wake:
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package cdp
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ysmood/kit"
+)
+
+// ReconnectPolicy decides how the client redials the browser when the websocket connection is broken.
+// The reconnection assumes the remote browser is still the same one, so targets and their states
+// can be recovered after it.
+type ReconnectPolicy struct {
+	// Sleeper is called to create the backoff sleeper for each reconnection,
+	// the sleeper is called before each attempt.
+	// Default is a backoff that grows from 100ms to 5s.
+	Sleeper func() kit.Sleeper
+
+	// MaxAttempts of each reconnection, 0 means unlimited.
+	MaxAttempts int
+}
+
+// ConnectionLostError is returned to the calls that are sent to the browser but haven't got response
+// when the connection is lost. It's safe to retry them after the client reconnects.
+type ConnectionLostError struct {
+	// Err is the reason of the connection lost
+	Err error
+}
+
+// Error interface
+func (e *ConnectionLostError) Error() string {
+	return fmt.Sprintf("[cdp] connection lost, the call can be retried: %v", e.Err)
+}
+
+// Unwrap interface
+func (e *ConnectionLostError) Unwrap() error {
+	return e.Err
+}
+
+// Retriable is always true, the call never reached the browser or its response is lost
+func (e *ConnectionLostError) Retriable() bool {
+	return true
+}
+
+type brokenMsg struct {
+	conn WebsocketableConn
+	err  error
+}
+
+// Reconnect enables the auto reconnection with the policy, set it to nil to disable it.
+// It's disabled by default, when disabled the client will be closed once the connection is broken.
+func (cdp *Client) Reconnect(policy *ReconnectPolicy) *Client {
+	cdp.reconnect = policy
+	return cdp
+}
+
+// OnReconnect adds a function that will be called in a new goroutine each time the client reconnects successfully.
+// It's the chance to re-attach the targets and recover the states of them.
+func (cdp *Client) OnReconnect(fn func()) *Client {
+	cdp.onReconnect = append(cdp.onReconnect, fn)
+	return cdp
+}
+
+// Returns false if it fails to reconnect, the client will be closed.
+func (cdp *Client) reconnectE(reason error, inflight map[uint64]kit.Nil) bool {
+	if cdp.debug {
+		cdp.debugLog(reason)
+	}
+
+	if closer, ok := cdp.wsConn.(io.Closer); ok {
+		_ = closer.Close()
+	}
+
+	for id := range inflight {
+		callback, has := cdp.callbacks.Load(id)
+		if has {
+			select {
+			case callback.(chan *response) <- &response{ID: id, err: &ConnectionLostError{reason}}:
+			default:
+			}
+		}
+	}
+
+	sleeper := kit.BackoffSleeper(100*time.Millisecond, 5*time.Second, nil)
+	if cdp.reconnect.Sleeper != nil {
+		sleeper = cdp.reconnect.Sleeper()
+	}
+
+	for attempt := 1; cdp.reconnect.MaxAttempts == 0 || attempt <= cdp.reconnect.MaxAttempts; attempt++ {
+		err := sleeper(cdp.ctx)
+		if err != nil {
+			cdp.close(err)
+			return false
+		}
+
+		conn, err := cdp.ws.Connect(cdp.ctx, cdp.wsURL, cdp.header)
+		if err != nil {
+			if cdp.debug {
+				cdp.debugLog(err)
+			}
+			reason = err
+			continue
+		}
+
+		cdp.wsConn = conn
+
+		go cdp.readMsgFromBrowser()
+
+		for _, fn := range cdp.onReconnect {
+			go fn()
+		}
+
+		return true
+	}
+
+	cdp.close(reason)
+	return false
+}
@@ -0,0 +1,96 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/ysmood/kit"
+)
+
+// FrameType of the recorded message
+type FrameType string
+
+const (
+	// FrameTypeRequest is the message sent to the browser
+	FrameTypeRequest FrameType = "request"
+	// FrameTypeResponse is the message from browser that has an id
+	FrameTypeResponse FrameType = "response"
+	// FrameTypeEvent is the message from browser that has no id
+	FrameTypeEvent FrameType = "event"
+)
+
+// Frame is a line of the JSONL trace file
+type Frame struct {
+	Type FrameType       `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Recorder wraps a Websocketable and writes every request, response and event to the writer as JSONL.
+// The output can be served by the Replayer.
+type Recorder struct {
+	ws     Websocketable
+	lock   *sync.Mutex
+	writer io.Writer
+}
+
+var _ Websocketable = &Recorder{}
+
+// NewRecorder instance, if ws is nil, the DefaultWsClient will be used
+func NewRecorder(ws Websocketable, writer io.Writer) *Recorder {
+	if ws == nil {
+		ws = NewDefaultWsClient()
+	}
+	return &Recorder{ws: ws, lock: &sync.Mutex{}, writer: writer}
+}
+
+// Connect interface
+func (r *Recorder) Connect(ctx context.Context, url string, header http.Header) (WebsocketableConn, error) {
+	conn, err := r.ws.Connect(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+	return &recorderConn{conn, r}, nil
+}
+
+func (r *Recorder) write(t FrameType, data []byte) error {
+	line, err := json.Marshal(&Frame{t, data})
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, err = r.writer.Write(append(line, '\n'))
+	return err
+}
+
+type recorderConn struct {
+	conn     WebsocketableConn
+	recorder *Recorder
+}
+
+func (c *recorderConn) Send(data []byte) error {
+	err := c.recorder.write(FrameTypeRequest, data)
+	if err != nil {
+		return err
+	}
+	return c.conn.Send(data)
+}
+
+func (c *recorderConn) Read() ([]byte, error) {
+	data, err := c.conn.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	t := FrameTypeEvent
+	if kit.JSON(data).Get("id").Exists() {
+		t = FrameTypeResponse
+	}
+
+	return data, c.recorder.write(t, data)
+}
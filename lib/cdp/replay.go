@@ -0,0 +1,155 @@
+package cdp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/ysmood/kit"
+)
+
+// Replayer is a fake browser that serves the frames recorded by the Recorder.
+// Each request is matched with the first unused recorded request that has the same method and params,
+// then the events recorded before the matched response, the response itself, and the events recorded
+// after the response until the next request will be sent back.
+// If no recorded request matches, an Error response will be sent back.
+type Replayer struct {
+	lock   *sync.Mutex
+	frames []*Frame
+	used   map[int]bool
+	cursor int // the frames before it are already sent
+
+	queue *MessageQueue
+}
+
+var _ Websocketable = &Replayer{}
+
+// NewReplayer from the JSONL trace that is created by the Recorder
+func NewReplayer(trace io.Reader) (*Replayer, error) {
+	frames := []*Frame{}
+
+	scanner := bufio.NewScanner(trace)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var f Frame
+		err := json.Unmarshal(scanner.Bytes(), &f)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, &f)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		lock:   &sync.Mutex{},
+		frames: frames,
+		used:   map[int]bool{},
+		queue:  NewMessageQueue(),
+	}, nil
+}
+
+// Connect interface, the url and header are ignored
+func (r *Replayer) Connect(ctx context.Context, _ string, _ http.Header) (WebsocketableConn, error) {
+	return &replayerConn{ctx, r}, nil
+}
+
+type replayerConn struct {
+	ctx      context.Context
+	replayer *Replayer
+}
+
+func (c *replayerConn) Send(data []byte) error {
+	var req Request
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		return err
+	}
+
+	c.replayer.queue.Push(c.replayer.match(&req)...)
+	return nil
+}
+
+func (c *replayerConn) Read() ([]byte, error) {
+	return c.replayer.queue.Read(c.ctx)
+}
+
+// returns the messages to send back for the request
+func (r *Replayer) match(req *Request) [][]byte {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for i, f := range r.frames {
+		if f.Type != FrameTypeRequest || r.used[i] {
+			continue
+		}
+
+		var recorded Request
+		if json.Unmarshal(f.Data, &recorded) != nil ||
+			recorded.Method != req.Method ||
+			!sameJSON(recorded.Params, req.Params) {
+			continue
+		}
+
+		r.used[i] = true
+
+		for j := i + 1; j < len(r.frames); j++ {
+			res := r.frames[j]
+			if res.Type != FrameTypeResponse || kit.JSON([]byte(res.Data)).Get("id").Uint() != recorded.ID {
+				continue
+			}
+
+			var msg response
+			_ = json.Unmarshal(res.Data, &msg)
+			msg.ID = req.ID
+
+			// the events recorded after the response until the next request are sent too,
+			// such as the Page.loadEventFired after the response of the Page.navigate
+			end := j + 1
+			for end < len(r.frames) && r.frames[end].Type != FrameTypeRequest {
+				end++
+			}
+
+			list := r.flushEvents(j)
+			list = append(list, kit.MustToJSONBytes(&msg))
+			return append(list, r.flushEvents(end)...)
+		}
+	}
+
+	return [][]byte{kit.MustToJSONBytes(&response{
+		ID: req.ID,
+		Error: &Error{
+			Code:    -32601,
+			Message: "[cdp] no recorded response for the request",
+			Data:    fmt.Sprintf("%s %s", req.Method, kit.MustToJSON(req.Params)),
+		},
+	})}
+}
+
+// flushEvents returns the events between the cursor and the end, then moves the cursor to the end
+func (r *Replayer) flushEvents(end int) [][]byte {
+	list := [][]byte{}
+	for ; r.cursor < end; r.cursor++ {
+		if r.frames[r.cursor].Type == FrameTypeEvent {
+			list = append(list, r.frames[r.cursor].Data)
+		}
+	}
+	return list
+}
+
+func sameJSON(a, b interface{}) bool {
+	var x, y interface{}
+	_ = json.Unmarshal(kit.MustToJSONBytes(a), &x)
+	_ = json.Unmarshal(kit.MustToJSONBytes(b), &y)
+	return reflect.DeepEqual(x, y)
+}
@@ -56,3 +56,8 @@ func (c *DefaultWsConn) Read() (data []byte, err error) {
 	}
 	return
 }
+
+// Close the connection
+func (c *DefaultWsConn) Close() error {
+	return c.conn.Close()
+}
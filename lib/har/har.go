@@ -0,0 +1,262 @@
+// Package har defines the HTTP Archive format 1.2
+// Ref: http://www.softwareishard.com/blog/har-12-spec/
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/ysmood/kit"
+)
+
+// Version of the spec
+const Version = "1.2"
+
+// HAR is the root object of the archive
+type HAR struct {
+	Log *Log `json:"log"`
+}
+
+// Log of the archive
+type Log struct {
+	Version string   `json:"version"`
+	Creator *Creator `json:"creator"`
+	Pages   []*Page  `json:"pages,omitempty"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Creator of the archive
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Page that the entries belong to
+type Page struct {
+	StartedDateTime time.Time    `json:"startedDateTime"`
+	ID              string       `json:"id"`
+	Title           string       `json:"title"`
+	PageTimings     *PageTimings `json:"pageTimings"`
+}
+
+// PageTimings in milliseconds since the page starts, -1 if not available
+type PageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+// Entry of a request
+type Entry struct {
+	Pageref         string    `json:"pageref,omitempty"`
+	StartedDateTime time.Time `json:"startedDateTime"`
+	// Time in milliseconds, the sum of all the non-negative timings
+	Time            float64   `json:"time"`
+	Request         *Request  `json:"request"`
+	Response        *Response `json:"response"`
+	Cache           *Cache    `json:"cache"`
+	Timings         *Timings  `json:"timings"`
+	ServerIPAddress string    `json:"serverIPAddress,omitempty"`
+	Connection      string    `json:"connection,omitempty"`
+}
+
+// Request of the entry
+type Request struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []*Cookie    `json:"cookies"`
+	Headers     []*NameValue `json:"headers"`
+	QueryString []*NameValue `json:"queryString"`
+	PostData    *PostData    `json:"postData,omitempty"`
+	HeadersSize int64        `json:"headersSize"`
+	BodySize    int64        `json:"bodySize"`
+}
+
+// Response of the entry
+type Response struct {
+	Status      int64        `json:"status"`
+	StatusText  string       `json:"statusText"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []*Cookie    `json:"cookies"`
+	Headers     []*NameValue `json:"headers"`
+	Content     *Content     `json:"content"`
+	RedirectURL string       `json:"redirectURL"`
+	HeadersSize int64        `json:"headersSize"`
+	BodySize    int64        `json:"bodySize"`
+}
+
+// Cookie of the request or response
+type Cookie struct {
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Path     string     `json:"path,omitempty"`
+	Domain   string     `json:"domain,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	HTTPOnly bool       `json:"httpOnly,omitempty"`
+	Secure   bool       `json:"secure,omitempty"`
+}
+
+// NameValue pair, such as a header or a query param
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData of the request
+type PostData struct {
+	MimeType string       `json:"mimeType"`
+	Params   []*NameValue `json:"params"`
+	Text     string       `json:"text"`
+}
+
+// Content of the response
+type Content struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	// Encoding is "base64" if the Text is encoded
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Body decodes the Text of the content
+func (c *Content) Body() ([]byte, error) {
+	if c.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(c.Text)
+	}
+	return []byte(c.Text), nil
+}
+
+// Cache info, the recorder leaves it empty
+type Cache struct{}
+
+// Timings of the request in milliseconds, -1 if not available
+type Timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// New creates an empty archive
+func New(creator, version string) *HAR {
+	return &HAR{Log: &Log{
+		Version: Version,
+		Creator: &Creator{Name: creator, Version: version},
+		Entries: []*Entry{},
+	}}
+}
+
+// Clone returns a deep copy of the archive
+func (h *HAR) Clone() *HAR {
+	log := *h.Log
+	if log.Creator != nil {
+		creator := *log.Creator
+		log.Creator = &creator
+	}
+	if log.Pages != nil {
+		log.Pages = make([]*Page, len(h.Log.Pages))
+		for i, p := range h.Log.Pages {
+			log.Pages[i] = p.Clone()
+		}
+	}
+	if log.Entries != nil {
+		log.Entries = make([]*Entry, len(h.Log.Entries))
+		for i, e := range h.Log.Entries {
+			log.Entries[i] = e.Clone()
+		}
+	}
+	return &HAR{Log: &log}
+}
+
+// Clone returns a deep copy of the page
+func (p *Page) Clone() *Page {
+	page := *p
+	if p.PageTimings != nil {
+		timings := *p.PageTimings
+		page.PageTimings = &timings
+	}
+	return &page
+}
+
+// Clone returns a deep copy of the entry
+func (e *Entry) Clone() *Entry {
+	entry := *e
+	if e.Request != nil {
+		req := *e.Request
+		req.Cookies = cloneCookies(req.Cookies)
+		req.Headers = cloneNameValues(req.Headers)
+		req.QueryString = cloneNameValues(req.QueryString)
+		if req.PostData != nil {
+			data := *req.PostData
+			data.Params = cloneNameValues(data.Params)
+			req.PostData = &data
+		}
+		entry.Request = &req
+	}
+	if e.Response != nil {
+		res := *e.Response
+		res.Cookies = cloneCookies(res.Cookies)
+		res.Headers = cloneNameValues(res.Headers)
+		if res.Content != nil {
+			content := *res.Content
+			res.Content = &content
+		}
+		entry.Response = &res
+	}
+	if e.Cache != nil {
+		entry.Cache = &Cache{}
+	}
+	if e.Timings != nil {
+		timings := *e.Timings
+		entry.Timings = &timings
+	}
+	return &entry
+}
+
+func cloneCookies(list []*Cookie) []*Cookie {
+	if list == nil {
+		return nil
+	}
+	cloned := make([]*Cookie, len(list))
+	for i, c := range list {
+		cookie := *c
+		if c.Expires != nil {
+			expires := *c.Expires
+			cookie.Expires = &expires
+		}
+		cloned[i] = &cookie
+	}
+	return cloned
+}
+
+func cloneNameValues(list []*NameValue) []*NameValue {
+	if list == nil {
+		return nil
+	}
+	cloned := make([]*NameValue, len(list))
+	for i, nv := range list {
+		v := *nv
+		cloned[i] = &v
+	}
+	return cloned
+}
+
+// Load an archive
+func Load(r io.Reader) (*HAR, error) {
+	var h HAR
+	err := json.NewDecoder(r).Decode(&h)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// Save the archive to the path
+func (h *HAR) Save(path string) error {
+	return kit.OutputFile(path, h, nil)
+}
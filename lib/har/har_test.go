@@ -0,0 +1,79 @@
+package har_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/har"
+	"github.com/stretchr/testify/assert"
+	"github.com/ysmood/kit"
+)
+
+func TestSaveLoad(t *testing.T) {
+	h := har.New("rod", "v0")
+	h.Log.Entries = append(h.Log.Entries, &har.Entry{
+		Request:  &har.Request{Method: "GET", URL: "http://a.com"},
+		Response: &har.Response{Status: 200, Content: &har.Content{Text: "aGk=", Encoding: "base64"}},
+	})
+
+	p := filepath.Join("tmp", kit.RandString(8)+".har")
+	defer func() { _ = kit.Remove("tmp") }()
+	assert.Nil(t, h.Save(p))
+
+	f, err := os.Open(p)
+	assert.Nil(t, err)
+	defer func() { _ = f.Close() }()
+
+	loaded, err := har.Load(f)
+	assert.Nil(t, err)
+	assert.Equal(t, har.Version, loaded.Log.Version)
+
+	body, err := loaded.Log.Entries[0].Response.Content.Body()
+	assert.Nil(t, err)
+	assert.Equal(t, "hi", string(body))
+
+	body, err = (&har.Content{Text: "hi"}).Body()
+	assert.Nil(t, err)
+	assert.Equal(t, "hi", string(body))
+
+	_, err = har.Load(f)
+	assert.Error(t, err)
+}
+
+func TestClone(t *testing.T) {
+	expires := time.Unix(100, 0)
+	h := har.New("rod", "v0")
+	h.Log.Pages = []*har.Page{{ID: "page_1", PageTimings: &har.PageTimings{OnLoad: 1}}}
+	h.Log.Entries = append(h.Log.Entries, &har.Entry{
+		Request: &har.Request{
+			Method:   "POST",
+			Headers:  []*har.NameValue{{Name: "a", Value: "1"}},
+			Cookies:  []*har.Cookie{{Name: "c", Expires: &expires}},
+			PostData: &har.PostData{Text: "body", Params: []*har.NameValue{}},
+		},
+		Response: &har.Response{Status: 200, Content: &har.Content{Text: "ok"}},
+		Cache:    &har.Cache{},
+		Timings:  &har.Timings{Wait: 1},
+	})
+
+	c := h.Clone()
+	assert.Equal(t, h, c)
+
+	c.Log.Pages[0].PageTimings.OnLoad = 2
+	c.Log.Entries[0].Request.Headers[0].Value = "2"
+	*c.Log.Entries[0].Request.Cookies[0].Expires = time.Unix(200, 0)
+	c.Log.Entries[0].Request.PostData.Text = "changed"
+	c.Log.Entries[0].Response.Content.Text = "changed"
+	c.Log.Entries[0].Timings.Wait = 2
+	c.Log.Entries = append(c.Log.Entries, &har.Entry{})
+
+	assert.Equal(t, 1.0, h.Log.Pages[0].PageTimings.OnLoad)
+	assert.Equal(t, "1", h.Log.Entries[0].Request.Headers[0].Value)
+	assert.Equal(t, time.Unix(100, 0), *h.Log.Entries[0].Request.Cookies[0].Expires)
+	assert.Equal(t, "body", h.Log.Entries[0].Request.PostData.Text)
+	assert.Equal(t, "ok", h.Log.Entries[0].Response.Content.Text)
+	assert.Equal(t, 1.0, h.Log.Entries[0].Timings.Wait)
+	assert.Len(t, h.Log.Entries, 1)
+}
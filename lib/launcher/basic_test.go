@@ -49,6 +49,22 @@ func TestLaunch(t *testing.T) {
 	assert.NotEmpty(t, url)
 }
 
+func TestLaunchPipe(t *testing.T) {
+	l := launcher.New()
+	defer func() {
+		_ = kit.KillTree(l.PID())
+	}()
+
+	client := l.LaunchPipe().Connect()
+
+	res, err := client.Call(context.Background(), "", "Browser.getVersion", nil)
+	utils.E(err)
+	assert.NotEmpty(t, kit.JSON(res).Get("product").String())
+
+	_, has := l.Get("remote-debugging-port")
+	assert.False(t, has)
+}
+
 func TestLaunchUserMode(t *testing.T) {
 	l := launcher.NewUserMode()
 	defer func() {
@@ -100,6 +116,9 @@ func TestLaunchErr(t *testing.T) {
 	assert.Panics(t, func() {
 		launcher.New().Bin("not-exists").Launch()
 	})
+	assert.Panics(t, func() {
+		launcher.New().Bin("not-exists").LaunchPipe()
+	})
 	assert.Panics(t, func() {
 		launcher.New().Headless(false).Bin("not-exists").Launch()
 	})
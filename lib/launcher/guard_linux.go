@@ -0,0 +1,13 @@
+// +build linux
+
+package launcher
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// guardProcess kills the process once the current process exits
+func guardProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+}
@@ -0,0 +1,8 @@
+// +build !linux
+
+package launcher
+
+import "os/exec"
+
+// guardProcess does nothing, the platform has no way to kill the process once the current process exits
+func guardProcess(cmd *exec.Cmd) {}
@@ -13,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/defaults"
 	"github.com/go-rod/rod/lib/utils"
 	"github.com/ysmood/kit"
@@ -268,14 +269,7 @@ func (l *Launcher) LaunchE() (wsURL string, err error) {
 
 	defer l.ctxCancel()
 
-	bin := l.bin
-	if bin == "" {
-		var err error
-		b := NewBrowser()
-		b.Context = l.ctx
-		bin, err = b.Get()
-		utils.E(err)
-	}
+	bin := l.getBin()
 
 	var ll *leakless.Launcher
 	var cmd *exec.Cmd
@@ -343,11 +337,93 @@ func (l *Launcher) LaunchE() (wsURL string, err error) {
 	return GetWebSocketDebuggerURL(l.ctx, u)
 }
 
+// LaunchPipe launches the browser with the flag "--remote-debugging-pipe" and returns the client to control it.
+// The browser won't open any TCP port, the client talks to it via the fd 3 and 4 of the browser process.
+// It's not supported on Windows.
+func (l *Launcher) LaunchPipe() *cdp.Client {
+	c, err := l.LaunchPipeE()
+	utils.E(err)
+	return c
+}
+
+// LaunchPipeE doc is similar to the method LaunchPipe
+func (l *Launcher) LaunchPipeE() (client *cdp.Client, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = e.(error)
+		}
+	}()
+
+	if l.reap {
+		runReaper()
+	}
+
+	defer l.ctxCancel()
+
+	bin := l.getBin()
+
+	l.Delete("remote-debugging-port")
+	l.Set("remote-debugging-pipe")
+
+	// the browser reads commands from fd 3 and writes responses to fd 4
+	cmdReader, cmdWriter, err := os.Pipe()
+	utils.E(err)
+	resReader, resWriter, err := os.Pipe()
+	utils.E(err)
+
+	// the pipes must be the fd 3 and 4 of the browser itself, so it's not started via the leakless,
+	// unlike the LaunchE there's no way to reuse the browser after the pipes are closed,
+	// so it's killed once the current process exits if the platform supports it
+	cmd := exec.Command(bin, l.FormatArgs()...)
+	cmd.ExtraFiles = []*os.File{cmdReader, resWriter}
+	guardProcess(cmd)
+
+	if l.log != nil {
+		stdout, err := cmd.StdoutPipe()
+		utils.E(err)
+		stderr, err := cmd.StderrPipe()
+		utils.E(err)
+
+		go l.read(stdout)
+		go l.read(stderr)
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		_ = cmdWriter.Close()
+		_ = resReader.Close()
+	}
+	_ = cmdReader.Close()
+	_ = resWriter.Close()
+	utils.E(err)
+
+	go func() {
+		_ = cmd.Wait()
+		close(l.exit)
+	}()
+
+	l.pid = cmd.Process.Pid
+
+	return cdp.New("").Websocket(cdp.NewPipe(resReader, cmdWriter)), nil
+}
+
 // PID returns the browser process pid
 func (l *Launcher) PID() int {
 	return l.pid
 }
 
+func (l *Launcher) getBin() string {
+	if l.bin != "" {
+		return l.bin
+	}
+
+	b := NewBrowser()
+	b.Context = l.ctx
+	bin, err := b.Get()
+	utils.E(err)
+	return bin
+}
+
 func (l *Launcher) kill() {
 	p, err := os.FindProcess(l.pid)
 	if err == nil {
@@ -2,7 +2,11 @@ package launcher
 
 import (
 	"context"
+	"io/ioutil"
 	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -99,3 +103,30 @@ func TestLaunchErr(t *testing.T) {
 	_, err := l.LaunchE()
 	assert.Error(t, err)
 }
+
+func TestLaunchPipeFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the pipes can't be passed as the extra files on windows")
+	}
+
+	// a fake browser that echoes the messages from the fd 3 to the fd 4
+	dir, err := ioutil.TempDir("", "")
+	utils.E(err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	bin := filepath.Join(dir, "browser")
+	utils.E(ioutil.WriteFile(bin, []byte("#!/bin/sh\nexec cat <&3 >&4\n"), 0755))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := New().Bin(bin).Reap(false)
+	client, err := l.LaunchPipeE()
+	assert.Nil(t, err)
+	client = client.Context(ctx, cancel).Connect()
+
+	_, err = client.Call(ctx, "", "Browser.getVersion", nil)
+	assert.Nil(t, err)
+
+	cancel()
+	<-l.exit
+}
@@ -0,0 +1,266 @@
+package rod
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/kit"
+)
+
+// Locator finds the element lazily by a chain of selectors, the selectors are the same as the ElementE's.
+// Unlike the Element, it doesn't hold a remote object, the chain will be resolved again on every action,
+// so it won't be stale after the page re-renders.
+// The actions wait until the element is attached, and the Click and Input also wait until it's
+// visible, stable and enabled. Use the Page.Timeout to limit the waiting.
+type Locator struct {
+	page   *Page
+	parent *Locator
+
+	selector string
+	text     string
+	nth      *int
+}
+
+// LocatorFilter narrows the elements that a link of the Locator matches
+type LocatorFilter func(*Locator)
+
+// HasText only matches the elements whose text matches the regex
+func HasText(regex string) LocatorFilter {
+	return func(l *Locator) {
+		l.text = regex
+	}
+}
+
+// Nth only matches the nth (0-based) element of the matched ones
+func Nth(i int) LocatorFilter {
+	return func(l *Locator) {
+		l.nth = &i
+	}
+}
+
+// Locator creates a Locator of the elements that match the selector
+func (p *Page) Locator(selector string, filters ...LocatorFilter) *Locator {
+	l := &Locator{page: p, selector: selector}
+	for _, f := range filters {
+		f(l)
+	}
+	return l
+}
+
+// Locator creates a Locator of the descendants of the elements of l that match the selector
+func (l *Locator) Locator(selector string, filters ...LocatorFilter) *Locator {
+	child := l.page.Locator(selector, filters...)
+	child.parent = l
+	return child
+}
+
+// String of the chain, such as `form >> button:text(/Save/)`
+func (l *Locator) String() string {
+	list := []string{}
+	for _, link := range l.chain() {
+		list = append(list, link.linkString())
+	}
+	return strings.Join(list, " >> ")
+}
+
+// ElementE waits until the element is attached, then returns it
+func (l *Locator) ElementE() (*Element, error) {
+	return l.wait(false)
+}
+
+// HasE checks if the element is attached without waiting
+func (l *Locator) HasE() (bool, error) {
+	el, _, err := l.resolve()
+	releaseElement(el)
+	return el != nil, err
+}
+
+// ClickE waits until the element is actionable, then clicks it
+func (l *Locator) ClickE(button proto.InputMouseButton) error {
+	return l.retry(true, func(el *Element) error {
+		return el.ClickE(button)
+	})
+}
+
+// InputE waits until the element is actionable, then inputs the text to it
+func (l *Locator) InputE(text string) error {
+	return l.retry(true, func(el *Element) error {
+		return el.InputE(text)
+	})
+}
+
+// TextE waits until the element is attached, then returns its text
+func (l *Locator) TextE() (string, error) {
+	var text string
+	err := l.retry(false, func(el *Element) (err error) {
+		text, err = el.TextE()
+		return
+	})
+	return text, err
+}
+
+// retry resolves the element and runs the fn, it starts over if the element is replaced during the fn
+func (l *Locator) retry(actionable bool, fn func(*Element) error) error {
+	for {
+		el, err := l.wait(actionable)
+		if err != nil {
+			return err
+		}
+
+		err = fn(el)
+		releaseElement(el)
+		if isStaleErr(err) && l.page.ctx.Err() == nil {
+			continue
+		}
+		return err
+	}
+}
+
+// wait resolves the chain until the element is attached, and if actionable is true, until it's
+// visible, stable and enabled. On timeout, the error tells the last reason why it's not ready.
+func (l *Locator) wait(actionable bool) (*Element, error) {
+	var el *Element
+	var reason error
+	var box *proto.DOMRect
+
+	err := kit.Retry(l.page.ctx, Sleeper(), func() (bool, error) {
+		var failed *Locator
+		var err error
+
+		// the element of the last poll is not ready, it will be resolved again
+		releaseElement(el)
+
+		el, failed, err = l.resolve()
+		if isStaleErr(err) {
+			return false, nil
+		} else if err != nil {
+			return true, err
+		}
+		if el == nil {
+			reason = fmt.Errorf("%w: %s", newErr(ErrElementNotFound, failed), failed.describe(l))
+			return false, nil
+		}
+
+		if !actionable {
+			return true, nil
+		}
+
+		reason, err = l.checkActionable(el, &box)
+		if isStaleErr(err) {
+			return false, nil
+		} else if err != nil {
+			return true, err
+		}
+		return reason == nil, nil
+	})
+	if err != nil {
+		releaseElement(el)
+		if reason != nil && l.page.ctx.Err() != nil {
+			return nil, fmt.Errorf("%w (%s)", reason, err.Error())
+		}
+		return nil, err
+	}
+	return el, nil
+}
+
+// checkActionable returns the reason why the element is not ready for user actions, it returns nil if
+// the element is ready. The element is stable when its box is the same as the last check.
+func (l *Locator) checkActionable(el *Element, last **proto.DOMRect) (reason error, err error) {
+	visible, err := el.VisibleE()
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return fmt.Errorf("%w: %s is not visible", newErr(ErrNotActionable, l), l), nil
+	}
+
+	js, jsArgs := jsHelper("enabled", nil)
+	enabled, err := el.EvalE(true, js, jsArgs)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled.Value.Bool() {
+		return fmt.Errorf("%w: %s is disabled", newErr(ErrNotActionable, l), l), nil
+	}
+
+	box, err := el.BoxE()
+	if err != nil {
+		return nil, err
+	}
+	stable := *last != nil && **last == *box
+	*last = box
+	if !stable {
+		return fmt.Errorf("%w: %s is not stable", newErr(ErrNotActionable, l), l), nil
+	}
+
+	return nil, nil
+}
+
+// resolve the chain once, if the element is not found, failed is the first link that matches nothing
+func (l *Locator) resolve() (el *Element, failed *Locator, err error) {
+	chain := l.chain()
+	links := []map[string]interface{}{}
+	for _, link := range chain {
+		links = append(links, map[string]interface{}{
+			"selector": link.selector,
+			"text":     link.text,
+			"nth":      link.nth,
+		})
+	}
+	js, jsArgs := jsHelper("locate", Array{links})
+
+	res, err := l.page.EvalE(false, "", js, jsArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.Subtype == proto.RuntimeRemoteObjectSubtypeNode {
+		return l.page.ElementFromObject(res.ObjectID), nil, nil
+	}
+	return nil, chain[res.Value.Int()], nil
+}
+
+// chain returns the links from the root to l
+func (l *Locator) chain() []*Locator {
+	list := []*Locator{}
+	for link := l; link != nil; link = link.parent {
+		list = append([]*Locator{link}, list...)
+	}
+	return list
+}
+
+// describe the link in the chain of the l, such as `link 2 "button" of form >> button`
+func (l *Locator) describe(chain *Locator) string {
+	links := chain.chain()
+	for i, link := range links {
+		if link == l {
+			return fmt.Sprintf("link %d %q of %s", i+1, l.linkString(), chain)
+		}
+	}
+	return l.linkString()
+}
+
+func (l *Locator) linkString() string {
+	str := l.selector
+	if l.text != "" {
+		str += fmt.Sprintf(":text(/%s/)", l.text)
+	}
+	if l.nth != nil {
+		str += fmt.Sprintf(":nth(%d)", *l.nth)
+	}
+	return str
+}
+
+// releaseElement releases the remote object of the element if it's not nil, the locator resolves a new one each time
+func releaseElement(el *Element) {
+	if el != nil {
+		_ = el.ReleaseE()
+	}
+}
+
+// isStaleErr tells if the err is caused by the element or its document being replaced
+func isStaleErr(err error) bool {
+	return errors.Is(err, ErrObjectNotFound) || errors.Is(err, ErrContextDestroyed)
+}
@@ -0,0 +1,135 @@
+package rod
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/assets"
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/kit"
+)
+
+func (s *S) TestLocator() {
+	var lock sync.Mutex
+	found, enabled := false, false
+	x := 1.0
+
+	fake := newFake().
+		Handle("DOM.getBoxModel", func(call *cdptest.Call) (interface{}, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return &proto.DOMGetBoxModelResult{Model: &proto.DOMBoxModel{
+				Content: proto.DOMQuad{x, x, x + 2, x, x + 2, x + 2, x, x + 2},
+			}}, nil
+		}).
+		Handle("Runtime.callFunctionOn", func(call *cdptest.Call) (interface{}, error) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			fn := call.JSONParams().Get("functionDeclaration").String()
+			obj := &proto.RuntimeRemoteObject{Value: proto.NewJSON(nil)}
+			switch {
+			case fn == assets.Helper:
+				obj.ObjectID = "helper"
+			case strings.Contains(fn, "rod.locate"):
+				if found {
+					obj = &proto.RuntimeRemoteObject{
+						Type: "object", Subtype: proto.RuntimeRemoteObjectSubtypeNode, ObjectID: "el", Value: proto.NewJSON(nil),
+					}
+				} else {
+					obj.Value = proto.NewJSON(1)
+				}
+			case strings.Contains(fn, "rod.visible"):
+				obj.Value = proto.NewJSON(true)
+			case strings.Contains(fn, "rod.enabled"):
+				obj.Value = proto.NewJSON(enabled)
+			}
+			return &proto.RuntimeCallFunctionOnResult{Result: obj}, nil
+		})
+
+	p := s.newFakePage(fake)
+
+	locator := func(p *Page) *Locator {
+		return p.Locator("form").Locator("button", HasText("Save"), Nth(1))
+	}
+	timeout := func() *Locator {
+		return locator(p.Timeout(500 * time.Millisecond))
+	}
+
+	l := timeout()
+	s.Equal("form >> button:text(/Save/):nth(1)", l.String())
+
+	_, err := l.ElementE()
+	s.True(errors.Is(err, ErrElementNotFound))
+	s.Contains(err.Error(), `link 2 "button:text(/Save/):nth(1)" of form >> button:text(/Save/):nth(1)`)
+
+	var locate *cdptest.Call
+	for _, call := range fake.Calls("Runtime.callFunctionOn") {
+		if strings.Contains(call.JSONParams().Get("functionDeclaration").String(), "rod.locate") {
+			locate = call
+		}
+	}
+	s.JSONEq(`[
+		{"selector":"form","text":"","nth":null},
+		{"selector":"button","text":"Save","nth":1}
+	]`, locate.JSONParams().Get("arguments.1.value").Raw)
+
+	lock.Lock()
+	found = true
+	lock.Unlock()
+	s.Equal(proto.RuntimeRemoteObjectID("el"), timeout().Element().ObjectID)
+
+	_, err = timeout().wait(true)
+	s.True(errors.Is(err, ErrNotActionable))
+	s.Contains(err.Error(), "form >> button:text(/Save/):nth(1) is disabled")
+
+	// the box keeps moving
+	lock.Lock()
+	enabled = true
+	lock.Unlock()
+	stop := make(chan kit.Nil)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+			lock.Lock()
+			x++
+			lock.Unlock()
+		}
+	}()
+	_, err = timeout().wait(true)
+	close(stop)
+	s.True(errors.Is(err, ErrNotActionable))
+	s.Contains(err.Error(), "is not stable")
+
+	el, err := locator(p).wait(true)
+	s.Nil(err)
+	s.Equal(proto.RuntimeRemoteObjectID("el"), el.ObjectID)
+
+	// the elements that are not handed out are released
+	fake.Reset()
+	s.True(locator(p).Has())
+	s.Equal("el", fake.Calls("Runtime.releaseObject")[0].JSONParams().Get("objectId").String())
+
+	fake.Reset()
+	s.Nil(locator(p).retry(false, func(el *Element) error { return nil }))
+	s.Equal(1, fake.Called("Runtime.releaseObject"))
+
+	// the stale element won't be retried after the page is done
+	ctx, cancel := context.WithCancel(p.ctx)
+	count := 0
+	err = locator(p.Context(ctx, cancel)).retry(false, func(el *Element) error {
+		count++
+		cancel()
+		return ErrObjectNotFound
+	})
+	s.True(errors.Is(err, ErrObjectNotFound))
+	s.Equal(1, count)
+}
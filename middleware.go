@@ -0,0 +1,42 @@
+package rod
+
+import (
+	"context"
+	"time"
+)
+
+// CDPMiddleware wraps the next CDPCall, such as for logging, metrics, rate-limiting or fault injection.
+// The sessionID and method of each call are the arguments of the CDPCall, use proto.ParseMethodName to
+// decode the method into domain and name.
+type CDPMiddleware func(next CDPCall) CDPCall
+
+// Use appends middlewares to the chain around the cdp calls of the browser, the first one is the outermost.
+// All the calls from the browser and its pages and elements will go through the chain.
+// A clone created by Browser.Context or Browser.Incognito copies the chain, so later changes to the clone
+// won't affect the original one.
+func (b *Browser) Use(middlewares ...CDPMiddleware) *Browser {
+	// the full slice expression makes the append copy, so the clones never share the backing array
+	b.middlewares = append(b.middlewares[:len(b.middlewares):len(b.middlewares)], middlewares...)
+	return b
+}
+
+// CDPTimeout returns a middleware that limits each call of the methods to d.
+// If methods is empty, all methods will be limited.
+func CDPTimeout(d time.Duration, methods ...string) CDPMiddleware {
+	list := map[string]bool{}
+	for _, m := range methods {
+		list[m] = true
+	}
+
+	return func(next CDPCall) CDPCall {
+		return func(ctx context.Context, sessionID, method string, params interface{}) ([]byte, error) {
+			if len(list) > 0 && !list[method] {
+				return next(ctx, sessionID, method, params)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx, sessionID, method, params)
+		}
+	}
+}
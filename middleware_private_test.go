@@ -0,0 +1,48 @@
+package rod
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func (s *S) TestMiddleware() {
+	fake := newFake().Handle("Runtime.evaluate", func(call *cdptest.Call) (interface{}, error) {
+		time.Sleep(100 * time.Millisecond)
+		return nil, nil
+	})
+
+	logs := []string{}
+	logger := func(name string) CDPMiddleware {
+		return func(next CDPCall) CDPCall {
+			return func(ctx context.Context, sessionID, method string, params interface{}) ([]byte, error) {
+				logs = append(logs, name+" "+method)
+				return next(ctx, sessionID, method, params)
+			}
+		}
+	}
+
+	b := s.connectFake(fake)
+	b.Use(logger("a"), logger("b")).Use(CDPTimeout(10*time.Millisecond, "Runtime.evaluate"))
+
+	p, err := b.PageE("")
+	s.Nil(err)
+	s.Equal([]string{"a Target.createTarget", "b Target.createTarget"}, logs[:2])
+
+	_, err = proto.RuntimeEvaluate{}.Call(p)
+	s.True(errors.Is(err, context.DeadlineExceeded))
+	s.Equal([]string{"a Runtime.evaluate", "b Runtime.evaluate"}, logs[len(logs)-2:])
+
+	// the siblings don't overwrite each other's chain
+	c1 := b.Context(context.WithCancel(b.GetContext()))
+	c2 := b.Context(context.WithCancel(b.GetContext()))
+	c1.Use(logger("c1"))
+	c2.Use(logger("c2"))
+	logs = []string{}
+	_, err = proto.BrowserGetVersion{}.Call(c1)
+	s.Nil(err)
+	s.Equal([]string{"a Browser.getVersion", "b Browser.getVersion", "c1 Browser.getVersion"}, logs)
+}
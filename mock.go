@@ -0,0 +1,318 @@
+package rod
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+// MockRoute is a declarative route of the HijackRouter. A request only hits the route when all the
+// matchers of the route pass, the requests that don't hit it will be passed to the next handler of the router.
+// Such as:
+//
+//	router.Route("POST", "*/api/users").JSON("name", "jack").Reply(201, map[string]int{"id": 1}).Times(1)
+//	router.Route("GET", "*/logo.png").ReplyFile("fixtures/logo.png")
+//	router.Route("", "*/slow").Delay(time.Second).Fail(proto.NetworkErrorReasonTimedOut)
+type MockRoute struct {
+	method  string
+	pattern string
+	hits    int64
+
+	// the route is configured after it's added to the router, the lock guards the fields below
+	lock     sync.Mutex
+	matchers []func(*HijackRequest) bool
+	reply    func(*Hijack) error
+	delay    time.Duration
+	times    int64
+}
+
+// RouteE adds a declarative mock route to the router, the method is ignored if it's empty,
+// the doc of the pattern is the same as "proto.FetchRequestPattern.URLPattern".
+// By default the route replies an empty response with status code 200.
+func (r *HijackRouter) RouteE(method, pattern string) (*MockRoute, error) {
+	route := &MockRoute{
+		method:  strings.ToUpper(method),
+		pattern: pattern,
+		reply:   func(*Hijack) error { return nil },
+		times:   -1,
+	}
+
+	err := r.AddE(pattern, "", route.handle)
+	if err != nil {
+		return nil, err
+	}
+
+	r.lock.Lock()
+	r.routes = append(r.routes, route)
+	r.lock.Unlock()
+
+	return route, nil
+}
+
+// Route adds a declarative mock route to the router
+func (r *HijackRouter) Route(method, pattern string) *MockRoute {
+	route, err := r.RouteE(method, pattern)
+	utils.E(err)
+	return route
+}
+
+// VerifyE checks the expectations of all the routes, such as the Times of each route.
+// Both the routes that are hit fewer times and the ones that are hit more times will be reported.
+func (r *HijackRouter) VerifyE() error {
+	r.lock.Lock()
+	routes := r.routes
+	r.lock.Unlock()
+
+	list := []string{}
+	for _, route := range routes {
+		route.lock.Lock()
+		times := int(route.times)
+		route.lock.Unlock()
+
+		hits := route.Hits()
+		if times < 0 || hits == times {
+			continue
+		}
+
+		problem := "too few"
+		if hits > times {
+			problem = "too many"
+		}
+		list = append(list, fmt.Sprintf("%s expected %d hits, got %d (%s)", route, times, hits, problem))
+	}
+	if len(list) > 0 {
+		return fmt.Errorf("%w: %s", ErrMockExpectation, strings.Join(list, "; "))
+	}
+	return nil
+}
+
+// Verify checks the expectations of all the routes
+func (r *HijackRouter) Verify() {
+	utils.E(r.VerifyE())
+}
+
+// String of the route
+func (m *MockRoute) String() string {
+	method := m.method
+	if method == "" {
+		method = "*"
+	}
+	return method + " " + m.pattern
+}
+
+// Regexp requires the url to match the regular expression
+func (m *MockRoute) Regexp(reg string) *MockRoute {
+	r := regexp.MustCompile(reg)
+	return m.Match(func(req *HijackRequest) bool {
+		return r.MatchString(req.event.Request.URL)
+	})
+}
+
+// Query requires the query param of the url to equal the value
+func (m *MockRoute) Query(key, value string) *MockRoute {
+	return m.Match(func(req *HijackRequest) bool {
+		query := req.URL().Query()
+		_, has := query[key]
+		return has && query.Get(key) == value
+	})
+}
+
+// Header requires the request header to equal the value, the key is case-insensitive
+func (m *MockRoute) Header(key, value string) *MockRoute {
+	return m.Match(func(req *HijackRequest) bool {
+		for k, v := range req.Headers() {
+			if strings.EqualFold(k, key) && v.String() == value {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// JSON requires the value at the path of the json request body to equal the value,
+// the doc of the path is the same as gjson.Get. The value will be compared after json encoding.
+func (m *MockRoute) JSON(path string, value interface{}) *MockRoute {
+	expected := normalizeJSON(value)
+	return m.Match(func(req *HijackRequest) bool {
+		res := req.JSONBody().Get(path)
+		return res.Exists() && reflect.DeepEqual(expected, normalizeJSON(res.Value()))
+	})
+}
+
+// Match adds a custom matcher to the route
+func (m *MockRoute) Match(fn func(req *HijackRequest) bool) *MockRoute {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.matchers = append(m.matchers, fn)
+	return m
+}
+
+func (m *MockRoute) setReply(fn func(*Hijack) error) *MockRoute {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.reply = fn
+	return m
+}
+
+// Reply with the status code, body and headers, the body is handled the same as HijackResponse.SetBody.
+// The headers are key-value pairs.
+func (m *MockRoute) Reply(status int, body interface{}, headers ...string) *MockRoute {
+	return m.setReply(func(ctx *Hijack) error {
+		ctx.Response.SetStatusCode(status)
+		if len(headers) > 0 {
+			ctx.Response.SetHeader(headers...)
+		}
+		if body != nil {
+			ctx.Response.SetBody(body)
+		}
+		return nil
+	})
+}
+
+// ReplyFile replies the file as the body with status code 200, the Content-Type is detected from
+// the extension of the file, such as a json fixture will be replied as "application/json".
+// The file is read each time the route is hit.
+func (m *MockRoute) ReplyFile(path string) *MockRoute {
+	return m.setReply(func(ctx *Hijack) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(path))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		ctx.Response.SetStatusCode(http.StatusOK)
+		ctx.Response.SetHeader("Content-Type", contentType)
+		ctx.Response.SetBody(f)
+		return nil
+	})
+}
+
+// ReplyTemplate replies the rendered text/template as the body. The dot of the template has two fields,
+// the "Request" is the *HijackRequest, the "Data" is the data. Such as:
+//
+//	router.Route("GET", "*/hello").ReplyTemplate(200, `hello {{.Request.URL.Query.Get "name"}}`, nil)
+func (m *MockRoute) ReplyTemplate(status int, tpl string, data interface{}) *MockRoute {
+	t := template.Must(template.New(m.String()).Parse(tpl))
+
+	return m.setReply(func(ctx *Hijack) error {
+		buf := bytes.NewBuffer(nil)
+		err := t.Execute(buf, map[string]interface{}{
+			"Request": ctx.Request,
+			"Data":    data,
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx.Response.SetStatusCode(status)
+		ctx.Response.SetBody(buf.Bytes())
+		return nil
+	})
+}
+
+// Fail the request with the reason
+func (m *MockRoute) Fail(reason proto.NetworkErrorReason) *MockRoute {
+	return m.setReply(func(ctx *Hijack) error {
+		ctx.Response.Fail(reason)
+		return nil
+	})
+}
+
+// Delay the reply
+func (m *MockRoute) Delay(d time.Duration) *MockRoute {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.delay = d
+	return m
+}
+
+// Times limits the route to reply n times, after that the requests will be passed to the next handler,
+// but they are still counted as hits. The HijackRouter.Verify will check if the route is hit exactly n times.
+func (m *MockRoute) Times(n int) *MockRoute {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.times = int64(n)
+	return m
+}
+
+// Hits returns how many times the route is hit, including the hits beyond the Times limit
+func (m *MockRoute) Hits() int {
+	return int(atomic.LoadInt64(&m.hits))
+}
+
+func (m *MockRoute) match(req *HijackRequest, matchers []func(*HijackRequest) bool) bool {
+	if m.method != "" && m.method != req.Method() {
+		return false
+	}
+	for _, fn := range matchers {
+		if !fn(req) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *MockRoute) handle(ctx *Hijack) {
+	m.lock.Lock()
+	matchers, reply, delay, times := m.matchers, m.reply, m.delay, m.times
+	m.lock.Unlock()
+
+	if !m.match(ctx.Request, matchers) {
+		ctx.Skip = true
+		return
+	}
+
+	// count every hit so that Verify can report the extra ones
+	hits := atomic.AddInt64(&m.hits, 1)
+	if times >= 0 && hits > times {
+		ctx.Skip = true
+		return
+	}
+
+	if delay > 0 {
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		select {
+		case <-ctx.ctx.Done():
+			ctx.OnError(ctx.ctx.Err())
+			ctx.Response.Fail(proto.NetworkErrorReasonAborted)
+			return
+		case <-t.C:
+		}
+	}
+
+	err := reply(ctx)
+	if err != nil {
+		ctx.OnError(err)
+		ctx.Response.Fail(proto.NetworkErrorReasonFailed)
+	}
+}
+
+// normalizeJSON converts the value to the generic form of json decoding
+func normalizeJSON(value interface{}) interface{} {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var v interface{}
+	_ = json.Unmarshal(b, &v)
+	return v
+}
@@ -0,0 +1,109 @@
+package rod
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+func (s *S) TestMockRoute() {
+	fake := newFake()
+	b := s.connectFake(fake)
+
+	router := b.HijackRequests()
+	once := router.Route("post", "http://a.com/users").
+		Header("content-type", "application/json").
+		JSON("user.tags", []string{"a"}).
+		Reply(201, map[string]int{"id": 1}).
+		Times(1)
+	router.Route("GET", "http://a.com/*").Query("name", "jack").Regexp(`/hello\?`).
+		ReplyTemplate(200, `hi {{.Request.URL.Query.Get "name"}} {{.Data}}`, "!")
+	router.Route("GET", "http://a.com/*").Regexp(`\.js$`).ReplyFile("fixtures/worker.js")
+	router.Route("", "http://a.com/*").Match(func(req *HijackRequest) bool {
+		return req.Method() != "GET"
+	}).Delay(time.Millisecond).Fail(proto.NetworkErrorReasonAccessDenied)
+	router.Add("*", func(ctx *Hijack) {
+		ctx.ContinueRequest(&proto.FetchContinueRequest{})
+	})
+	go router.Run()
+
+	pause := func(id, method, u, body string) *cdptest.Call {
+		fake.Reset()
+		fake.Emit("", "Fetch.requestPaused", &proto.FetchRequestPaused{
+			RequestID: proto.FetchRequestID(id),
+			Request: &proto.NetworkRequest{Method: method, URL: u, PostData: body, Headers: proto.NetworkHeaders{
+				"Content-Type": proto.NewJSON("application/json"),
+			}},
+		})
+		var call *cdptest.Call
+		s.waitUntil(func() bool {
+			for _, c := range fake.Calls("") {
+				if c.JSONParams().Get("requestId").String() == id {
+					call = c
+					return true
+				}
+			}
+			return false
+		})
+		return call
+	}
+	body := func(call *cdptest.Call) string {
+		b, err := base64.StdEncoding.DecodeString(call.JSONParams().Get("body").String())
+		utils.E(err)
+		return string(b)
+	}
+
+	s.True(errors.Is(router.VerifyE(), ErrMockExpectation))
+
+	call := pause("1", "POST", "http://a.com/users", `{"user":{"tags":["a"]}}`)
+	s.Equal("Fetch.fulfillRequest", call.Method)
+	s.EqualValues(201, call.JSONParams().Get("responseCode").Int())
+	s.Equal(`{"id":1}`, body(call))
+	s.Equal(1, once.Hits())
+	s.Nil(router.VerifyE())
+
+	// the route is used up
+	call = pause("2", "POST", "http://a.com/users", `{"user":{"tags":["a"]}}`)
+	s.Equal("Fetch.failRequest", call.Method)
+	s.Equal("AccessDenied", call.JSONParams().Get("errorReason").String())
+	s.Equal(2, once.Hits())
+	s.True(errors.Is(router.VerifyE(), ErrMockExpectation))
+	s.Contains(router.VerifyE().Error(), "expected 1 hits, got 2 (too many)")
+
+	s.Equal("hi jack !", body(pause("3", "GET", "http://a.com/hello?name=jack", "")))
+	s.Equal("Fetch.continueRequest", pause("4", "GET", "http://a.com/hello?name=tom", "").Method)
+
+	call = pause("5", "GET", "http://a.com/worker.js", "")
+	s.Contains(call.JSONParams().Get(`responseHeaders.#(name=="Content-Type").value`).String(), "javascript")
+	s.Contains(body(call), "postMessage")
+}
+
+func (s *S) TestMockRouteDelayCanceled() {
+	fake := newFake()
+	b := s.connectFake(fake)
+
+	router := b.HijackRequests()
+	route := router.Route("GET", "*").Delay(time.Hour).Times(2)
+	go router.Run()
+
+	s.Contains(router.VerifyE().Error(), "expected 2 hits, got 0 (too few)")
+
+	fake.Emit("", "Fetch.requestPaused", &proto.FetchRequestPaused{
+		RequestID: "1",
+		Request:   &proto.NetworkRequest{Method: "GET", URL: "http://a.com"},
+	})
+	s.waitUntil(func() bool {
+		return route.Hits() == 1
+	})
+
+	router.Stop()
+	s.waitUntil(func() bool {
+		return fake.Called("Fetch.failRequest") == 1
+	})
+	s.Equal("Aborted", fake.Calls("Fetch.failRequest")[0].JSONParams().Get("errorReason").String())
+	s.Equal(0, fake.Called("Fetch.fulfillRequest"))
+}
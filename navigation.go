@@ -0,0 +1,181 @@
+package rod
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/kit"
+)
+
+// NavigationUntil is the lifecycle event of the new document that a navigation waits for
+type NavigationUntil string
+
+const (
+	// NavigationUntilLoad waits for the load event
+	NavigationUntilLoad NavigationUntil = "load"
+	// NavigationUntilDOMContentLoaded waits for the DOMContentLoaded event
+	NavigationUntilDOMContentLoaded NavigationUntil = "DOMContentLoaded"
+	// NavigationUntilNetworkIdle waits until there's no network connection for at least 500ms
+	NavigationUntilNetworkIdle NavigationUntil = "networkIdle"
+	// NavigationUntilNetworkAlmostIdle waits until there're no more than 2 network connections for at least 500ms
+	NavigationUntilNetworkAlmostIdle NavigationUntil = "networkAlmostIdle"
+)
+
+// how long DetectNavigationE waits for the action to request a navigation
+var navigationSettle = 100 * time.Millisecond
+
+// BackE navigates to the previous entry of the history, it does nothing if there's no previous entry
+func (p *Page) BackE() error {
+	return p.historyGo(-1)
+}
+
+// ForwardE navigates to the next entry of the history, it does nothing if there's no next entry
+func (p *Page) ForwardE() error {
+	return p.historyGo(1)
+}
+
+func (p *Page) historyGo(delta int) error {
+	res, err := proto.PageGetNavigationHistory{}.Call(p)
+	if err != nil {
+		return err
+	}
+
+	i := int(res.CurrentIndex) + delta
+	if i < 0 || i >= len(res.Entries) {
+		return nil
+	}
+
+	return proto.PageNavigateToHistoryEntry{EntryID: res.Entries[i].ID}.Call(p)
+}
+
+// ReloadE the page, if ignoreCache is true the browser cache will be ignored, like the Shift+Refresh
+func (p *Page) ReloadE(ignoreCache bool) error {
+	return proto.PageReload{IgnoreCache: ignoreCache}.Call(p)
+}
+
+// WaitNavigationE returns a wait function that waits until the next navigation of the frame reaches the until event.
+// The navigations within the document, such as the history.pushState or the hash change, will resolve it immediately.
+func (p *Page) WaitNavigationE(until NavigationUntil) func() error {
+	n, err := p.watchNavigation(until)
+
+	return func() error {
+		if err != nil {
+			return err
+		}
+		defer n.stop()
+		return n.wait()
+	}
+}
+
+// DetectNavigationE runs the action, such as clicking a link or submitting a form, if the action triggers
+// a navigation of the frame it will wait until the until event of the new document.
+// If no navigation is requested shortly after the action, navigated will be false.
+func (p *Page) DetectNavigationE(until NavigationUntil, action func() error) (navigated bool, err error) {
+	n, err := p.watchNavigation(until)
+	if err != nil {
+		return false, err
+	}
+	defer n.stop()
+
+	err = action()
+	if err != nil {
+		return false, err
+	}
+
+	timer := time.NewTimer(navigationSettle)
+	defer timer.Stop()
+
+	select {
+	case <-n.requested:
+	case <-timer.C:
+		return false, nil
+	case <-n.ctx.Done():
+		return false, n.ctx.Err()
+	}
+
+	return true, n.wait()
+}
+
+type navigation struct {
+	ctx       context.Context
+	stop      func()
+	requested chan kit.Nil // closed when a navigation of the frame is requested or started
+	done      chan kit.Nil // closed when the navigation reaches the until event
+}
+
+func (n *navigation) wait() error {
+	select {
+	case <-n.done:
+		return nil
+	case <-n.ctx.Done():
+		return n.ctx.Err()
+	}
+}
+
+func (p *Page) watchNavigation(until NavigationUntil) (*navigation, error) {
+	frameID, err := p.frameID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = proto.PageSetLifecycleEventsEnabled{Enabled: true}.Call(p)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	n := &navigation{
+		ctx:       ctx,
+		stop:      cancel,
+		requested: make(chan kit.Nil),
+		done:      make(chan kit.Nil),
+	}
+
+	once := &sync.Once{}
+	request := func() { once.Do(func() { close(n.requested) }) }
+
+	// the lifecycle events of the current document will be ignored, because they don't have the init event
+	var loaderID proto.NetworkLoaderID
+
+	wait := p.browser.eachEvent(ctx, p.sessionID(), func(
+		req *proto.PageFrameRequestedNavigation,
+		life *proto.PageLifecycleEvent,
+		within *proto.PageNavigatedWithinDocument,
+	) bool {
+		switch {
+		case req != nil:
+			if req.FrameID == frameID && (req.Disposition == "" ||
+				req.Disposition == proto.PageClientNavigationDispositionCurrentTab) {
+				request()
+			}
+
+		case life != nil:
+			if life.FrameID != frameID {
+				return false
+			}
+			if life.Name == "init" {
+				loaderID = life.LoaderID
+				request()
+				return false
+			}
+			if loaderID != "" && life.LoaderID == loaderID && life.Name == string(until) {
+				close(n.done)
+				return true
+			}
+
+		case within != nil:
+			if within.FrameID == frameID {
+				request()
+				close(n.done)
+				return true
+			}
+		}
+		return false
+	})
+
+	go wait()
+
+	return n, nil
+}
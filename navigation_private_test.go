@@ -0,0 +1,78 @@
+package rod
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func (s *S) TestNavigation() {
+	fake := newFake().
+		Handle("Page.getFrameTree", cdptest.Result(&proto.PageGetFrameTreeResult{
+			FrameTree: &proto.PageFrameTree{Frame: &proto.PageFrame{ID: "main"}},
+		})).
+		Handle("Page.getNavigationHistory", cdptest.Result(&proto.PageGetNavigationHistoryResult{
+			CurrentIndex: 1,
+			Entries:      []*proto.PageNavigationEntry{{ID: 10}, {ID: 11}},
+		}))
+
+	p := s.newFakePage(fake)
+
+	p.Back().Forward()
+	s.Len(fake.Calls("Page.navigateToHistoryEntry"), 1)
+	s.EqualValues(10, fake.Calls("Page.navigateToHistoryEntry")[0].JSONParams().Get("entryId").Int())
+
+	p.Reload().ReloadIgnoreCache()
+	s.False(fake.Calls("Page.reload")[0].JSONParams().Get("ignoreCache").Bool())
+	s.True(fake.Calls("Page.reload")[1].JSONParams().Get("ignoreCache").Bool())
+
+	life := func(frameID, loaderID, name string) {
+		fake.Emit("s", "Page.lifecycleEvent", &proto.PageLifecycleEvent{
+			FrameID: proto.PageFrameID(frameID), LoaderID: proto.NetworkLoaderID(loaderID), Name: name,
+		})
+	}
+
+	wait := p.WaitNavigation(NavigationUntilLoad)
+	s.True(fake.Calls("Page.setLifecycleEventsEnabled")[0].JSONParams().Get("enabled").Bool())
+	life("main", "0", "load") // the current document
+	life("other", "1", "init")
+	life("main", "1", "init")
+	life("main", "1", "DOMContentLoaded")
+	life("main", "1", "load")
+	wait()
+
+	// the action doesn't navigate
+	old := navigationSettle
+	navigationSettle = time.Millisecond
+	defer func() { navigationSettle = old }()
+	s.False(p.DetectNavigation(NavigationUntilLoad, func() {}))
+
+	// the action opens a new tab
+	s.False(p.DetectNavigation(NavigationUntilLoad, func() {
+		fake.Emit("s", "Page.frameRequestedNavigation", &proto.PageFrameRequestedNavigation{
+			FrameID: "main", Disposition: proto.PageClientNavigationDispositionNewTab,
+		})
+	}))
+
+	navigationSettle = time.Minute
+	s.True(p.DetectNavigation(NavigationUntilNetworkIdle, func() {
+		fake.Emit("s", "Page.frameRequestedNavigation", &proto.PageFrameRequestedNavigation{
+			FrameID: "main", Disposition: proto.PageClientNavigationDispositionCurrentTab,
+		})
+		life("main", "2", "init")
+		life("main", "2", "load")
+		life("main", "2", "networkIdle")
+	}))
+	s.True(p.DetectNavigation(NavigationUntilLoad, func() {
+		fake.Emit("s", "Page.navigatedWithinDocument", &proto.PageNavigatedWithinDocument{FrameID: "main"})
+	}))
+
+	_, err := p.Timeout(10*time.Millisecond).DetectNavigationE(NavigationUntilLoad, func() error {
+		life("main", "3", "init")
+		return nil
+	})
+	s.True(errors.Is(err, context.DeadlineExceeded))
+}
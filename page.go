@@ -32,8 +32,11 @@ type Page struct {
 
 	browser *Browser
 
-	TargetID  proto.TargetTargetID
-	SessionID proto.TargetSessionID
+	// An iframe page switches them to the ones of the out-of-process iframe once the iframe is resolved,
+	// the switching is guarded by the sessionLock
+	TargetID    proto.TargetTargetID
+	SessionID   proto.TargetSessionID
+	sessionLock *sync.RWMutex
 
 	// devices
 	Mouse    *Mouse
@@ -43,11 +46,13 @@ type Page struct {
 	windowObjectID   proto.RuntimeRemoteObjectID // used as the thisObject when eval js
 	jsHelperObjectID proto.RuntimeRemoteObjectID
 	executionIDs     map[proto.PageFrameID]proto.RuntimeExecutionContextID
+	frames           *pageFrames
+	console          *pageConsole
 
 	event *goob.Observable
 }
 
-// IsIframe tells if it's iframe
+// IsIframe tells if it's iframe, the out-of-process ones included
 func (p *Page) IsIframe() bool {
 	return p.element != nil
 }
@@ -65,7 +70,7 @@ func (p *Page) Root() *Page {
 
 // InfoE of the page, such as the URL or title of the page
 func (p *Page) InfoE() (*proto.TargetTargetInfo, error) {
-	return p.browser.pageInfo(p.TargetID)
+	return p.browser.pageInfo(p.targetID())
 }
 
 // CookiesE returns the page cookies. By default it will return the cookies for current page.
@@ -133,13 +138,17 @@ func (p *Page) NavigateE(url string) error {
 		return err
 	}
 	if res.ErrorText != "" {
-		return fmt.Errorf("%w: %s", newErr(ErrNavigation, res.ErrorText), res.ErrorText)
+		e := ErrNavigation
+		if res.ErrorText == "net::ERR_ABORTED" {
+			e = ErrNavigationAborted
+		}
+		return fmt.Errorf("%w: %s", newErr(e, res.ErrorText), res.ErrorText)
 	}
 	return nil
 }
 
 func (p *Page) getWindowID() (proto.BrowserWindowID, error) {
-	res, err := proto.BrowserGetWindowForTarget{TargetID: p.TargetID}.Call(p)
+	res, err := proto.BrowserGetWindowForTarget{TargetID: p.targetID()}.Call(p)
 	if err != nil {
 		return 0, err
 	}
@@ -289,7 +298,7 @@ func (p *Page) WaitOpenE() func() (*Page, error) {
 	var targetID proto.TargetTargetID
 
 	wait := b.EachEvent(func(e *proto.TargetTargetCreated) bool {
-		if e.TargetInfo.OpenerID == p.TargetID {
+		if e.TargetInfo.OpenerID == p.targetID() {
 			targetID = e.TargetInfo.TargetID
 			return true
 		}
@@ -346,12 +355,12 @@ func (p *Page) PauseE() error {
 // The fn can accpet multiple events, such as EachEventE(func(e1 *proto.PageLoadEventFired, e2 *proto.PageLifecycleEvent) {}),
 // only one argument will be non-null, others will null.
 func (p *Page) EachEvent(fn interface{}) (wait func()) {
-	return p.browser.eachEvent(p.ctx, p.SessionID, fn)
+	return p.browser.eachEvent(p.ctx, p.sessionID(), fn)
 }
 
 // WaitEvent waits for the next event for one time. It will also load the data into the event object.
 func (p *Page) WaitEvent(e proto.Payload) (wait func()) {
-	return p.browser.waitEvent(p.ctx, p.SessionID, e)
+	return p.browser.waitEvent(p.ctx, p.sessionID(), e)
 }
 
 // WaitRequestIdleE returns a wait function that waits until no request for d duration.
@@ -376,7 +385,7 @@ func (p *Page) WaitRequestIdleE(d time.Duration, includes, excludes []string) fu
 		cancel()
 	}()
 
-	wait := p.browser.eachEvent(ctx, p.SessionID, func(
+	wait := p.browser.eachEvent(ctx, p.sessionID(), func(
 		sent *proto.NetworkRequestWillBeSent,
 		finished *proto.NetworkLoadingFinished, // not use responseReceived because https://crbug.com/883475
 		failed *proto.NetworkLoadingFailed,
@@ -478,6 +487,23 @@ func (p *Page) ExposeE(name string) (callback chan string, stop func(), err erro
 // Set the byValue to true to reduce memory occupation.
 // If the item in jsArgs is proto.RuntimeRemoteObjectID, the remote object will be used, else the item will be treated as JSON value.
 func (p *Page) EvalE(byValue bool, thisID proto.RuntimeRemoteObjectID, js string, jsArgs Array) (*proto.RuntimeRemoteObject, error) {
+	res, err := p.callFunction(byValue, true, thisID, SprintFnThis(js), jsArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.ExceptionDetails != nil {
+		exp := res.ExceptionDetails.Exception
+		return nil, fmt.Errorf("%w: %s %s", newErr(ErrEval, exp), exp.Description, exp.Value.String())
+	}
+
+	return res.Result, nil
+}
+
+// callFunction calls the function declaration on the thisID, the exception details of the js are left to the caller
+func (p *Page) callFunction(
+	byValue, awaitPromise bool, thisID proto.RuntimeRemoteObjectID, fn string, jsArgs Array,
+) (*proto.RuntimeCallFunctionOnResult, error) {
 	backoff := kit.BackoffSleeper(30*time.Millisecond, 3*time.Second, nil)
 	objectID := thisID
 	var err error
@@ -512,9 +538,9 @@ func (p *Page) EvalE(byValue bool, thisID proto.RuntimeRemoteObjectID, js string
 
 		res, err = proto.RuntimeCallFunctionOn{
 			ObjectID:            objectID,
-			AwaitPromise:        true,
+			AwaitPromise:        awaitPromise,
 			ReturnByValue:       byValue,
-			FunctionDeclaration: SprintFnThis(js),
+			FunctionDeclaration: fn,
 			Arguments:           args,
 		}.Call(p)
 		if thisID == "" && isNilContextErr(err) {
@@ -525,16 +551,7 @@ func (p *Page) EvalE(byValue bool, thisID proto.RuntimeRemoteObjectID, js string
 		return true, err
 	})
 
-	if err != nil {
-		return nil, err
-	}
-
-	if res.ExceptionDetails != nil {
-		exp := res.ExceptionDetails.Exception
-		return nil, fmt.Errorf("%w: %s %s", newErr(ErrEval, exp), exp.Description, exp.Value.String())
-	}
-
-	return res.Result, nil
+	return res, err
 }
 
 // WaitE js function until it returns true
@@ -637,7 +654,7 @@ func (p *Page) ReleaseE(objectID proto.RuntimeRemoteObjectID) error {
 
 // CallContext parameters for proto
 func (p *Page) CallContext() (context.Context, proto.Client, string) {
-	return p.ctx, p.browser, string(p.SessionID)
+	return p.ctx, p.browser, string(p.sessionID())
 }
 
 func (p *Page) initSession() error {
@@ -698,7 +715,7 @@ func (p *Page) initJS(force bool) error {
 
 // We use this function to make sure every frame(page, iframe) will only have one IsolatedWorld.
 func (p *Page) getExecutionID(force bool) (proto.RuntimeExecutionContextID, error) {
-	if !p.IsIframe() {
+	if !p.IsIframe() || p.isOutOfProcess() {
 		return 0, nil
 	}
 
@@ -707,6 +724,33 @@ func (p *Page) getExecutionID(force bool) (proto.RuntimeExecutionContextID, erro
 		return 0, err
 	}
 
+	err = p.startFrames()
+	if err != nil {
+		return 0, err
+	}
+
+	// the out-of-process iframe that is already auto-attached
+	if sessionID, has := p.frames.sessions.Load(frameID); has {
+		p.useFrameSession(frameID, sessionID.(proto.TargetSessionID))
+		return 0, nil
+	}
+
+	ctxID, err := p.isolatedWorld(frameID, force)
+	if err == nil {
+		return ctxID, nil
+	}
+
+	// the frame has no execution context in the session, check if it's an out-of-process iframe
+	sessionID, e := p.frameSession(frameID)
+	if e != nil || sessionID == "" {
+		return 0, err
+	}
+	p.useFrameSession(frameID, sessionID)
+	return 0, nil
+}
+
+// isolatedWorld returns the execution context of the frame in the session of the page
+func (p *Page) isolatedWorld(frameID proto.PageFrameID, force bool) (proto.RuntimeExecutionContextID, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -736,7 +780,7 @@ func (p *Page) getExecutionID(force bool) (proto.RuntimeExecutionContextID, erro
 
 func (p *Page) frameID() (proto.PageFrameID, error) {
 	// this is the only way we can get the window object from the iframe
-	if p.IsIframe() {
+	if p.IsIframe() && !p.isOutOfProcess() {
 		node, err := p.element.DescribeE(1, false)
 		if err != nil {
 			return "", err
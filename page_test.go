@@ -3,17 +3,21 @@ package rod_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"image/png"
+	"net/http"
 	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/devices"
 	"github.com/go-rod/rod/lib/input"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/rod/lib/utils"
+	"github.com/tidwall/gjson"
 	"github.com/ysmood/kit"
 )
 
@@ -225,6 +229,106 @@ func (s *S) TestPageEval() {
 	s.NotEqualValues(1, page.Eval(`/* ) */`))
 }
 
+func (s *S) TestPageEvaluate() {
+	page := s.page.Navigate(srcFile("fixtures/click.html"))
+	button := page.Element("button")
+
+	var res struct {
+		Tag   string
+		Count int
+	}
+	page.Evaluate(rod.Eval(`async (opts) => {
+		await new Promise(r => setTimeout(r, 10))
+		return { tag: opts.els[0].tagName, count: opts.count + 1 }
+	}`, map[string]interface{}{"els": []*rod.Element{button}, "count": 1}), &res)
+	s.Equal("BUTTON", res.Tag)
+	s.Equal(2, res.Count)
+
+	var tag string
+	button.Evaluate(rod.Eval(`function() { return this.tagName }`), &tag)
+	s.Equal("BUTTON", tag)
+
+	opts := rod.Eval(`() => Promise.resolve(1)`)
+	opts.AwaitPromise = false
+	var obj map[string]interface{}
+	page.Evaluate(opts, &obj)
+	s.Len(obj, 0)
+
+	err := page.EvaluateE(rod.Eval(`() => Promise.reject(new Error('boom'))`), nil)
+	var exp *rod.JSException
+	s.True(errors.As(err, &exp))
+	s.Contains(exp.Description, "Error: boom")
+}
+
+func (s *S) TestPageAccessibilityTree() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/", ginHTML(`<html><title>Settings</title><body>
+		<h2>Profile</h2>
+		<label>Name <input value="rod"></label>
+		<div aria-hidden="true"><button>Hidden</button></div>
+		<button id="save">Save</button>
+	</body></html>`))
+
+	page := s.browser.Page(url)
+	defer page.Close()
+
+	tree := page.AccessibilityTree()
+	s.Equal("RootWebArea", tree.Role)
+	s.Equal("Settings", tree.Name)
+	s.NotNil(tree.Find("heading", "Profile"))
+	s.Equal("rod", tree.Find("textbox", "Name").Value)
+	s.Nil(tree.Find("button", "Hidden"))
+
+	button := tree.Find("button", "Save")
+	s.Equal("save", button.Element().Eval(`this.id`).String())
+
+	node := page.Element("#save").AXNode()
+	s.Equal("button", node.Role)
+	s.Equal("Save", node.Name)
+	s.True(node.Properties["focusable"].Bool())
+}
+
+func (s *S) TestPageAuditAccessibility() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/", ginHTML(`<html><title>Audit</title><body>
+		<h1>Top</h1>
+		<h3>Deep</h3>
+		<img src="banner.png">
+		<input id="name">
+		<button id="empty"></button>
+		<a href="#"></a>
+		<p style="color: #ccc; background: #fff">Faint</p>
+		<div id="dup">A</div><div id="dup">B</div>
+		<button>OK</button>
+	</body></html>`))
+
+	page := s.browser.Page(url)
+	defer page.Close()
+
+	report := page.AuditAccessibility()
+	s.Equal(url+"/", report.URL)
+
+	for _, rule := range rod.AccessibilityRules {
+		s.NotEmpty(report.Filter(rule), rule)
+	}
+
+	button := report.Filter(rod.AccessibilityRuleButtonName)[0]
+	s.Equal(rod.SeverityCritical, button.Severity)
+	s.Equal("#empty", button.Selector)
+	s.Equal("empty", button.Element.Eval(`this.id`).String())
+
+	s.Len(report.Filter(rod.AccessibilityRuleDuplicateID), 2)
+	s.Equal("html > body > p", report.Filter(rod.AccessibilityRuleColorContrast)[0].Selector)
+	s.Contains(report.Filter(rod.AccessibilityRuleHeadingOrder)[0].Message, "from 1 to 3")
+
+	s.Len(gjson.ParseBytes(report.JSON()).Get("violations").Array(), len(report.Violations))
+	s.Contains(string(report.JUnit()), `<testcase classname="accessibility" name="image-alt">`)
+}
+
 func (s *S) TestPageExposeJSHelper() {
 	page := s.browser.Page(srcFile("fixtures/click.html"))
 	defer page.Close()
@@ -279,6 +383,67 @@ func (s *S) TestPageWait() {
 	page.Wait(`document.querySelector('button') !== null`)
 }
 
+func (s *S) TestPageRecordHAR() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/redirect", func(ctx kit.GinContext) { ctx.Redirect(http.StatusFound, "/") })
+	engine.GET("/", ginHTML(`<html><script>fetch('/data?a=1')</script></html>`))
+	engine.GET("/data", func(ctx kit.GinContext) { ctx.String(http.StatusOK, "ok") })
+
+	page := s.browser.Page("")
+	defer page.Close()
+
+	r := page.RecordHAR()
+	wait := page.WaitRequestIdle()
+	page.Navigate(url + "/redirect")
+	wait()
+	h := r.Stop()
+
+	s.Len(h.Log.Pages, 1)
+	s.GreaterOrEqual(len(h.Log.Entries), 3)
+	s.EqualValues(http.StatusFound, h.Log.Entries[0].Response.Status)
+	s.Equal(url+"/", h.Log.Entries[0].Response.RedirectURL)
+	s.Equal("a", h.Log.Entries[2].Request.QueryString[0].Name)
+	s.Equal("ok", h.Log.Entries[2].Response.Content.Text)
+	s.GreaterOrEqual(h.Log.Entries[2].Time, 0.0)
+}
+
+func (s *S) TestPageDownloads() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/", ginHTML(`<html><form method="POST" action="/d"><button>download</button></form></html>`))
+	engine.POST("/d", func(ctx kit.GinContext) {
+		ctx.Header("Content-Disposition", `attachment; filename="file.txt"`)
+		ctx.String(http.StatusOK, "content")
+	})
+
+	dir := filepath.Join("tmp", kit.RandString(8))
+	defer func() { _ = kit.Remove(dir) }()
+
+	page := s.browser.Page(url)
+	defer page.Close()
+
+	m := page.Downloads(dir)
+	defer m.Close()
+
+	wait := m.Wait()
+	page.Element("button").Click()
+	d := wait()
+	d.Wait()
+
+	s.Equal("file.txt", d.SuggestedFilename)
+	received, _ := d.Progress()
+	s.EqualValues(7, received)
+
+	to := filepath.Join(dir, d.SuggestedFilename)
+	d.SaveAs(to)
+	content, err := kit.ReadString(to)
+	utils.E(err)
+	s.Equal("content", content)
+}
+
 func (s *S) TestPageWaitRequestIdle() {
 	url, engine, close := serve()
 	defer close()
@@ -320,6 +485,102 @@ func (s *S) TestPageWaitRequestIdle() {
 	wait()
 }
 
+func (s *S) TestPageNavigationHistory() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/:name", func(ctx kit.GinContext) {
+		ginHTML(`<p>` + ctx.Param("name") + `</p>`)(ctx)
+	})
+
+	page := s.browser.Page("")
+	defer page.Close()
+
+	wait := page.WaitNavigation(rod.NavigationUntilLoad)
+	page.Navigate(url + "/a")
+	wait()
+	wait = page.WaitNavigation(rod.NavigationUntilDOMContentLoaded)
+	page.Navigate(url + "/b")
+	wait()
+
+	wait = page.WaitNavigation(rod.NavigationUntilLoad)
+	page.Back()
+	wait()
+	s.Equal("a", page.Element("p").Text())
+
+	wait = page.WaitNavigation(rod.NavigationUntilNetworkAlmostIdle)
+	page.Forward()
+	wait()
+	s.Equal("b", page.Element("p").Text())
+
+	wait = page.WaitNavigation(rod.NavigationUntilNetworkIdle)
+	page.ReloadIgnoreCache()
+	wait()
+	s.Equal("b", page.Element("p").Text())
+}
+
+func (s *S) TestPageDetectNavigation() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/", ginHTML(`<html>
+	<a href="/next">link</a>
+	<button id="none">none</button>
+	<form action="/next" method="post"><button id="submit">submit</button></form>
+	</html>`))
+	engine.Any("/next", ginHTML(`<p>next</p>`))
+
+	page := s.browser.Page(url)
+	defer page.Close()
+
+	s.False(page.DetectNavigation(rod.NavigationUntilLoad, func() {
+		page.Element("#none").Click()
+	}))
+
+	s.True(page.DetectNavigation(rod.NavigationUntilLoad, func() {
+		page.Element("a").Click()
+	}))
+	s.Equal("next", page.Element("p").Text())
+
+	page.Navigate(url)
+	s.True(page.DetectNavigation(rod.NavigationUntilDOMContentLoaded, func() {
+		page.Element("#submit").Click()
+	}))
+	s.Equal("next", page.Element("p").Text())
+}
+
+func (s *S) TestPageConsole() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/", ginHTML(`<html><script>
+		console.log('before', { a: 1 })
+	</script></html>`))
+
+	page := s.browser.Page(url)
+	defer page.Close()
+
+	// the messages logged before the capturing starts are also reported
+	logs := make(chan *rod.ConsoleMessage, 10)
+	page.OnConsole(func(msg *rod.ConsoleMessage) { logs <- msg })
+
+	exceptions := make(chan *rod.JSException, 1)
+	page.OnException(func(e *rod.JSException) { exceptions <- e })
+
+	msg := <-logs
+	s.Equal("log", msg.Type)
+	s.Equal("before", msg.Args[0].String())
+	s.EqualValues(1, msg.Args[1].Get("a").Int())
+
+	page.Eval(`() => { console.error('after'); setTimeout(() => { throw new Error('boom') }) }`)
+
+	msg = <-logs
+	s.Equal("error", msg.Type)
+	s.Equal("after", msg.Text)
+	s.Contains((<-exceptions).Error(), "Error: boom")
+	s.GreaterOrEqual(len(page.ConsoleMessages()), 2)
+}
+
 func (s *S) TestPageWaitIdle() {
 	p := s.page.Navigate(srcFile("fixtures/click.html"))
 	p.Element("button").Click()
@@ -551,6 +812,29 @@ func (s *S) TestPageExpose() {
 	})
 }
 
+func (s *S) TestPageExposeFunc() {
+	page := s.browser.Page("")
+	defer page.Close()
+
+	stop := page.ExposeFunc("exposedAdd", func(args []gjson.Result) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, errors.New("two args required")
+		}
+		return map[string]int64{"sum": args[0].Int() + args[1].Int()}, nil
+	})
+
+	s.EqualValues(3, page.Eval(`async () => (await exposedAdd(1, 2)).sum`).Int())
+
+	// survives navigations
+	page.Navigate(srcFile("fixtures/click.html"))
+	s.EqualValues(5, page.Eval(`async () => (await exposedAdd(2, 3)).sum`).Int())
+	s.Equal("two args required", page.Eval(`() => exposedAdd().catch(e => e.message)`).String())
+
+	stop()
+	page.Navigate(srcFile("fixtures/click.html"))
+	s.Equal("undefined", page.Eval(`typeof exposedAdd`).String())
+}
+
 func (s *S) TestNavigateErr() {
 	// dns error
 	s.Panics(func() {
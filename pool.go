@@ -0,0 +1,227 @@
+package rod
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/kit"
+)
+
+// Pool manages a fixed number of slots, each slot owns a browser or an incognito context and a page of it.
+// Only one page of a slot can be used at a time, so the size of the pool caps the concurrency.
+// The slots are created lazily when they are needed.
+type Pool struct {
+	create  func() (*Browser, error)
+	maxUses int
+
+	slots chan *poolSlot
+	done  chan kit.Nil // closed when the pool is closed
+
+	lock   *sync.Mutex
+	inUse  map[proto.TargetTargetID]*poolSlot
+	closed bool
+}
+
+type poolSlot struct {
+	browser *Browser
+	page    *Page
+	uses    int
+}
+
+// NewPool creates a pool with the size of slots, the create function is called to create the
+// browser of a slot, the pool owns the returned browser and will close it when the slot is recycled.
+// If the browser is an incognito one, only its browser context will be disposed.
+func NewPool(size int, create func() (*Browser, error)) *Pool {
+	p := &Pool{
+		create: create,
+		slots:  make(chan *poolSlot, size),
+		done:   make(chan kit.Nil),
+		lock:   &sync.Mutex{},
+		inUse:  map[proto.TargetTargetID]*poolSlot{},
+	}
+	for i := 0; i < size; i++ {
+		p.slots <- &poolSlot{}
+	}
+	return p
+}
+
+// NewBrowserPool creates a pool that launches a new browser for each slot
+func NewBrowserPool(size int) *Pool {
+	return NewPool(size, func() (*Browser, error) {
+		b := New()
+		return b, b.ConnectE()
+	})
+}
+
+// NewIncognitoPool creates a pool that creates a new incognito context of the browser for each slot
+func NewIncognitoPool(b *Browser, size int) *Pool {
+	return NewPool(size, b.IncognitoE)
+}
+
+// MaxUses sets how many times a slot can be used before its browser gets recycled.
+// Default is 0, which means unlimited.
+func (pool *Pool) MaxUses(n int) *Pool {
+	pool.maxUses = n
+	return pool
+}
+
+// GetE doc is similar to the method Get
+func (pool *Pool) GetE(ctx context.Context) (*Page, error) {
+	var s *poolSlot
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-pool.done:
+		return nil, ErrPoolClosed
+	case s = <-pool.slots:
+	}
+
+	pool.lock.Lock()
+	closed := pool.closed
+	pool.lock.Unlock()
+	if closed {
+		pool.slots <- s
+		return nil, ErrPoolClosed
+	}
+
+	// the browser is closed or crashed
+	if s.browser != nil && s.browser.ctx.Err() != nil {
+		_ = pool.recycle(s)
+	}
+
+	if s.browser == nil {
+		b, err := pool.create()
+		if err != nil {
+			pool.slots <- s
+			return nil, err
+		}
+		s.browser = b
+	}
+
+	if s.page == nil {
+		page, err := s.browser.PageE("")
+		if err != nil {
+			_ = pool.recycle(s)
+			pool.slots <- s
+			return nil, err
+		}
+		s.page = page
+	}
+
+	pool.lock.Lock()
+	pool.inUse[s.page.TargetID] = s
+	pool.lock.Unlock()
+
+	return s.page, nil
+}
+
+// PutE doc is similar to the method Put
+func (pool *Pool) PutE(page *Page) error {
+	pool.lock.Lock()
+	s, has := pool.inUse[page.TargetID]
+	delete(pool.inUse, page.TargetID)
+	closed := pool.closed
+	pool.lock.Unlock()
+
+	if !has {
+		return ErrNotFromPool
+	}
+	defer func() { pool.slots <- s }()
+
+	s.uses++
+	if closed || (pool.maxUses > 0 && s.uses >= pool.maxUses) {
+		return pool.recycle(s)
+	}
+
+	err := resetPage(s.page)
+	if err != nil {
+		// the page is probably crashed, a fresh browser is safer than a broken one
+		_ = pool.recycle(s)
+		return err
+	}
+	return nil
+}
+
+// CloseE doc is similar to the method Close
+func (pool *Pool) CloseE() error {
+	pool.lock.Lock()
+	if !pool.closed {
+		pool.closed = true
+		close(pool.done)
+	}
+	pool.lock.Unlock()
+
+	var err error
+	idle := []*poolSlot{}
+	for len(idle) < cap(pool.slots) {
+		var s *poolSlot
+		select {
+		case s = <-pool.slots:
+		default: // the slots in use will be recycled when they are put back
+		}
+		if s == nil {
+			break
+		}
+		if e := pool.recycle(s); e != nil {
+			err = e
+		}
+		idle = append(idle, s)
+	}
+	for _, s := range idle {
+		pool.slots <- s
+	}
+	return err
+}
+
+// recycle closes the browser of the slot and empties the slot
+func (pool *Pool) recycle(s *poolSlot) error {
+	b := s.browser
+	s.browser = nil
+	s.page = nil
+	s.uses = 0
+
+	if b == nil || b.ctx.Err() != nil {
+		return nil
+	}
+	if b.BrowserContextID != "" {
+		return proto.TargetDisposeBrowserContext{BrowserContextID: b.BrowserContextID}.Call(b)
+	}
+	return b.CloseE()
+}
+
+// resetPage clears the cookies, storage and viewport of the page, then navigates it to blank
+func resetPage(p *Page) error {
+	info, err := p.InfoE()
+	if err != nil {
+		return err
+	}
+
+	err = proto.NetworkClearBrowserCookies{}.Call(p)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(info.URL)
+	if err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		err = proto.StorageClearDataForOrigin{
+			Origin:       u.Scheme + "://" + u.Host,
+			StorageTypes: "all",
+		}.Call(p)
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.browser.defaultViewport == nil {
+		err = proto.EmulationClearDeviceMetricsOverride{}.Call(p)
+	} else {
+		err = p.ViewportE(p.browser.defaultViewport)
+	}
+	if err != nil {
+		return err
+	}
+
+	return p.NavigateE("about:blank")
+}
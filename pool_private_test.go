@@ -0,0 +1,59 @@
+package rod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp/cdptest"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func (s *S) TestPool() {
+	var count int64
+	fake := newFake().
+		Handle("Target.createTarget", func(*cdptest.Call) (interface{}, error) {
+			return &proto.TargetCreateTargetResult{
+				TargetID: proto.TargetTargetID(fmt.Sprint(atomic.AddInt64(&count, 1))),
+			}, nil
+		}).
+		Handle("Target.createBrowserContext", cdptest.Result(&proto.TargetCreateBrowserContextResult{BrowserContextID: "ctx"})).
+		Handle("Target.getTargetInfo", cdptest.Result(&proto.TargetGetTargetInfoResult{
+			TargetInfo: &proto.TargetTargetInfo{URL: "http://a.com/path"},
+		}))
+
+	b := s.connectFake(fake)
+	ctx := b.GetContext()
+	pool := NewIncognitoPool(b, 1).MaxUses(2)
+
+	p := pool.Get(ctx)
+	s.EqualValues("1", p.TargetID)
+
+	timeout, timeoutCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer timeoutCancel()
+	_, err := pool.GetE(timeout)
+	s.True(errors.Is(err, context.DeadlineExceeded))
+
+	fake.Reset()
+	pool.Put(p)
+	s.Equal("http://a.com", fake.Calls("Storage.clearDataForOrigin")[0].JSONParams().Get("origin").String())
+	s.Equal(1, fake.Called("Network.clearBrowserCookies"))
+	s.Equal("about:blank", fake.Calls("Page.navigate")[0].JSONParams().Get("url").String())
+
+	p = pool.Get(ctx)
+	s.EqualValues("1", p.TargetID)
+	pool.Put(p)
+	s.Equal("ctx", fake.Calls("Target.disposeBrowserContext")[0].JSONParams().Get("browserContextId").String())
+
+	p = pool.Get(ctx)
+	s.EqualValues("2", p.TargetID)
+	s.Equal(ErrNotFromPool, pool.PutE(&Page{TargetID: "other"}))
+
+	pool.Close()
+	_, err = pool.GetE(ctx)
+	s.Equal(ErrPoolClosed, err)
+	pool.Put(p)
+	s.Len(fake.Calls("Target.disposeBrowserContext"), 2)
+}
@@ -5,9 +5,14 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/go-rod/rod/lib/cdp/cdptest"
 	"github.com/go-rod/rod/lib/defaults"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
 	"github.com/stretchr/testify/suite"
+	"github.com/ysmood/kit"
 )
 
 // S test suite
@@ -20,6 +25,36 @@ func Test(t *testing.T) {
 	suite.Run(t, s)
 }
 
+// newFake returns a fake browser that attaches the pages to the session "s",
+// the window object of the pages is "window" and the js functions return null by default
+func newFake() *cdptest.Browser {
+	return cdptest.New().
+		Handle("Target.attachToTarget", cdptest.Result(&proto.TargetAttachToTargetResult{SessionID: "s"})).
+		Handle("Runtime.evaluate", cdptest.Object("window")).
+		Handle("Runtime.callFunctionOn", cdptest.Value(nil))
+}
+
+// connectFake connects a browser to the fake, the browser will be closed when the test ends
+func (s *S) connectFake(fake *cdptest.Browser) *Browser {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.T().Cleanup(cancel)
+	return New().Context(ctx, cancel).Client(fake.Client()).Connect()
+}
+
+// newFakePage connects a browser to the fake and creates a page of it
+func (s *S) newFakePage(fake *cdptest.Browser) *Page {
+	return s.connectFake(fake).Page("")
+}
+
+// waitUntil retries the cond until it returns true
+func (s *S) waitUntil(cond func() bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	utils.E(kit.Retry(ctx, Sleeper(), func() (bool, error) {
+		return cond(), nil
+	}))
+}
+
 func (s *S) TestDefaultTraceLoggers() {
 	defaultTraceLogAct("msg")
 	defaultTraceLogJS("fn", Array{1, 2})
@@ -32,7 +67,7 @@ func (s *S) TestUpdateMouseTracerErr() {
 	cdpCall := func(ctx context.Context, sessionID, method string, params interface{}) ([]byte, error) {
 		return nil, errors.New("err")
 	}
-	m := &Mouse{page: &Page{ctx: ctx, lock: &sync.Mutex{}, browser: &Browser{cdpCall: cdpCall}}}
+	m := &Mouse{page: &Page{ctx: ctx, lock: &sync.Mutex{}, sessionLock: &sync.RWMutex{}, browser: &Browser{cdpCall: cdpCall}}}
 
 	s.True(m.updateMouseTracer())
 }
@@ -91,7 +91,16 @@ func (p *Page) HasMatchesE(pairs ...string) (bool, error) {
 	return err == nil, err
 }
 
-// ElementE finds element by css selector
+// ElementE finds element by css selector. The selector can also be addressed to a selector engine by its prefix:
+//
+//	text=Save                  the innermost elements that have the text
+//	role=button[name="Save"]   the elements that have the aria role and states, such as the accessible name
+//	label=Email                the form controls that have the label
+//	placeholder=Email          the elements that have the placeholder
+//	testid=submit              the elements that have the data-testid attribute
+//
+// The text is matched case-insensitively as a substring, quote it for the exact match, or use the /regex/flags.
+// Except the css, the selector engines pierce the open shadow roots.
 func (p *Page) ElementE(sleeper kit.Sleeper, objectID proto.RuntimeRemoteObjectID, selectors []string) (*Element, error) {
 	js, jsArgs := jsHelper("element", ArrayFromList(selectors))
 	return p.ElementByJSE(sleeper, objectID, js, jsArgs)
@@ -2,6 +2,7 @@ package rod_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/defaults"
@@ -186,3 +187,83 @@ func (s *S) TestElementsOthers() {
 	s.Nil(list.First())
 	s.Nil(list.Last())
 }
+
+func (s *S) TestLocator() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/", ginHTML(`<html>
+		<form>
+			<input>
+			<button disabled>Cancel</button>
+			<button disabled>Save</button>
+		</form>
+		<p></p>
+		<script>
+			// re-render the form, the old nodes will be stale
+			setTimeout(() => {
+				const form = document.querySelector('form')
+				form.innerHTML = form.innerHTML.replace(/ disabled/g, '')
+				form.querySelectorAll('button').forEach(b => {
+					b.onclick = (e) => { e.preventDefault(); document.querySelector('p').innerText = b.innerText }
+				})
+			}, 500)
+		</script>
+	</html>`))
+
+	page := s.browser.Page(url)
+	defer page.Close()
+
+	form := page.Locator("form")
+	stale := form.Locator("button", rod.HasText("Save")).Element()
+
+	form.Locator("input").Input("rod")
+	form.Locator("button", rod.HasText("Save")).Click()
+	s.Equal("Save", page.Locator("p").Text())
+	s.Equal("rod", form.Locator("input").Text())
+	s.False(stale.Eval(`this.isConnected`).Bool())
+
+	s.True(form.Locator("button", rod.Nth(1)).Has())
+	s.False(form.Locator("button", rod.Nth(2)).Has())
+
+	_, err := page.Timeout(time.Second).Locator("form").Locator("a").ElementE()
+	s.True(errors.Is(err, rod.ErrElementNotFound))
+	s.Contains(err.Error(), `link 2 "a" of form >> a`)
+}
+
+func (s *S) TestSelectorEngines() {
+	url, engine, close := serve()
+	defer close()
+
+	engine.GET("/", ginHTML(`<html>
+		<label for="email">Email</label>
+		<input id="email" placeholder="Your email" data-testid="email-input">
+		<input type="checkbox" aria-label="Remember" checked>
+		<h2>Settings</h2>
+		<button><span>Save</span></button>
+		<button>Save all</button>
+		<div id="host"></div>
+		<script>
+			const root = document.querySelector('#host').attachShadow({ mode: 'open' })
+			root.innerHTML = '<a href="#">Shadow Link</a>'
+		</script>
+	</html>`))
+
+	page := s.browser.Page(url)
+	defer page.Close()
+
+	s.Equal("SPAN", page.Element(`text="Save"`).Eval(`this.tagName`).String())
+	s.Len(page.Elements("text=save"), 2)
+	s.Equal("Save", page.Element(`role=button[name="Save"]`).Text())
+	s.Equal("Save all", page.Element(`role=button[name=/all$/]`).Text())
+	s.Equal("Settings", page.Element(`role=heading[level=2]`).Text())
+	s.True(page.Has(`role=checkbox[name="Remember"][checked=true]`))
+	s.False(page.Has(`role=checkbox[checked=false]`))
+	s.Equal("email", page.Element("label=Email").Eval(`this.id`).String())
+	s.Equal("email", page.Element("placeholder=your email").Eval(`this.id`).String())
+	s.Equal("email", page.Element("testid=email-input").Eval(`this.id`).String())
+
+	// pierce the open shadow roots
+	s.Equal("Shadow Link", page.Element(`role=link`).Text())
+	s.Equal("Shadow Link", page.Locator("#host").Locator("text=shadow").Text())
+}
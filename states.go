@@ -100,6 +100,63 @@ func (b *Browser) DisableDomain(ctx context.Context, sessionID proto.TargetSessi
 	}
 }
 
+// the states that need to be replayed to recover a session
+func isStateful(methodName string) bool {
+	switch methodName {
+	case "Target.setDiscoverTargets",
+		"Target.setAutoAttach",
+		"Page.setLifecycleEventsEnabled",
+		"Emulation.setDeviceMetricsOverride",
+		"Emulation.setGeolocationOverride":
+		return true
+	}
+	_, name := proto.ParseMethodName(methodName)
+	return name == "enable"
+}
+
+// recoverStates re-attaches the known pages after the cdp client reconnects,
+// then replays the recorded states to both the browser and the new sessions.
+// The pages keep their original session ids, calls will be redirected to the new sessions.
+func (b *Browser) recoverStates() {
+	b.replayStates("")
+
+	b.states.Range(func(_, v interface{}) bool {
+		page, ok := v.(*Page)
+		if !ok {
+			return true
+		}
+
+		res, err := proto.TargetAttachToTarget{TargetID: page.TargetID, Flatten: true}.Call(b)
+		if err != nil {
+			return true // the target may be closed during the disconnection
+		}
+
+		b.sessions.Store(page.SessionID, res.SessionID)
+		b.replayStates(page.SessionID)
+		return true
+	})
+}
+
+func (b *Browser) replayStates(sessionID proto.TargetSessionID) {
+	b.states.Range(func(k, v interface{}) bool {
+		key, ok := k.(stateKey)
+		if ok && key.sessionID == sessionID && isStateful(key.methodName) {
+			_, _ = b.call(b.ctx, string(sessionID), key.methodName, v.(json.RawMessage))
+		}
+		return true
+	})
+}
+
+func (b *Browser) currentSession(sessionID proto.TargetSessionID) proto.TargetSessionID {
+	if b.sessions == nil || sessionID == "" {
+		return sessionID
+	}
+	if id, has := b.sessions.Load(sessionID); has {
+		return id.(proto.TargetSessionID)
+	}
+	return sessionID
+}
+
 func (b *Browser) storePage(page *Page) {
 	b.states.Store(page.TargetID, page)
 }
@@ -113,17 +170,17 @@ func (b *Browser) loadPage(id proto.TargetTargetID) *Page {
 
 // LoadState into the method.
 func (p *Page) LoadState(method proto.Payload) (has bool) {
-	return p.browser.LoadState(p.SessionID, method)
+	return p.browser.LoadState(p.sessionID(), method)
 }
 
 // EnableDomain and returns a recover function to restore previous state
 func (p *Page) EnableDomain(method proto.Payload) (recover func()) {
-	return p.browser.EnableDomain(p.ctx, p.SessionID, method)
+	return p.browser.EnableDomain(p.ctx, p.sessionID(), method)
 }
 
 // DisableDomain and returns a recover function to restore previous state
 func (p *Page) DisableDomain(method proto.Payload) (recover func()) {
-	return p.browser.DisableDomain(p.ctx, p.SessionID, method)
+	return p.browser.DisableDomain(p.ctx, p.sessionID(), method)
 }
 
 func (p *Page) cleanupStates() {
@@ -3,6 +3,7 @@
 package rod
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-rod/rod/lib/devices"
@@ -61,6 +62,16 @@ func (b *Browser) HandleAuth(username, password string) {
 	go func() { utils.E(wait()) }()
 }
 
+// HandleAuthWith answers every auth challenge of the browser with the provider, such as:
+//
+//	router := browser.HandleAuthWith(rod.NewAuthStore().Set("https://a.com", "", "user", "pass").Provide)
+//	defer router.Stop()
+func (b *Browser) HandleAuthWith(provider AuthProvider) *HijackRouter {
+	r, err := b.HandleAuthWithE(provider)
+	utils.E(err)
+	return r
+}
+
 // FindByURL returns the page that has the url that matches the regex
 func (ps Pages) FindByURL(regex string) *Page {
 	p, err := ps.FindByURLE(regex)
@@ -112,6 +123,49 @@ func (p *Page) Navigate(url string) *Page {
 	return p
 }
 
+// Back navigates to the previous entry of the history
+func (p *Page) Back() *Page {
+	utils.E(p.BackE())
+	return p
+}
+
+// Forward navigates to the next entry of the history
+func (p *Page) Forward() *Page {
+	utils.E(p.ForwardE())
+	return p
+}
+
+// Reload the page
+func (p *Page) Reload() *Page {
+	utils.E(p.ReloadE(false))
+	return p
+}
+
+// ReloadIgnoreCache reloads the page without using the browser cache
+func (p *Page) ReloadIgnoreCache() *Page {
+	utils.E(p.ReloadE(true))
+	return p
+}
+
+// WaitNavigation returns a wait function that waits until the next navigation of the frame reaches the until event
+func (p *Page) WaitNavigation(until NavigationUntil) (wait func()) {
+	w := p.WaitNavigationE(until)
+	return func() {
+		utils.E(w())
+	}
+}
+
+// DetectNavigation runs the action, such as clicking a link or submitting a form, if the action triggers
+// a navigation it will wait until the until event of the new document.
+func (p *Page) DetectNavigation(until NavigationUntil, action func()) (navigated bool) {
+	navigated, err := p.DetectNavigationE(until, func() error {
+		action()
+		return nil
+	})
+	utils.E(err)
+	return navigated
+}
+
 // GetWindow get window bounds
 func (p *Page) GetWindow() *proto.BrowserBounds {
 	bounds, err := p.GetWindowE()
@@ -191,6 +245,20 @@ func (p *Page) Close() {
 	utils.E(p.CloseE())
 }
 
+// FrameTree of the page, the out-of-process iframes are merged into it
+func (p *Page) FrameTree() *proto.PageFrameTree {
+	tree, err := p.FrameTreeE()
+	utils.E(err)
+	return tree
+}
+
+// Frames of the page, the parent frames are always before their children
+func (p *Page) Frames() []*proto.PageFrame {
+	list, err := p.FramesE()
+	utils.E(err)
+	return list
+}
+
 // HandleDialog accepts or dismisses next JavaScript initiated dialog (alert, confirm, prompt, or onbeforeunload)
 // Because alert will block js, usually you have to run the wait function inside a goroutine. Check the unit test
 // for it for more information.
@@ -298,6 +366,14 @@ func (p *Page) Expose(name string) (callback chan string, stop func()) {
 	return c, s
 }
 
+// ExposeFunc exposes the fn to the window object of the page as window[name], the js calls of it return promises.
+// For example: page.ExposeFunc("add", fn) then call it in the page via `await add(1, 2)`
+func (p *Page) ExposeFunc(name string, fn func(args []gjson.Result) (interface{}, error)) (stop func()) {
+	stop, err := p.ExposeFuncE(name, fn)
+	utils.E(err)
+	return stop
+}
+
 // Eval js on the page. The first param must be a js function definition.
 // For example page.Eval(`n => n + 1`, 1) will return 2
 func (p *Page) Eval(js string, params ...interface{}) proto.JSON {
@@ -306,6 +382,12 @@ func (p *Page) Eval(js string, params ...interface{}) proto.JSON {
 	return res.Value
 }
 
+// Evaluate the opts and decode the result into the result, the promise returned by the js will be awaited by default.
+// For example: page.Evaluate(rod.Eval(`(a, b) => a + b`, 1, 2), &sum)
+func (p *Page) Evaluate(opts *EvalOptions, result interface{}) {
+	utils.E(p.EvaluateE(opts, result))
+}
+
 // Wait js function until it returns true
 func (p *Page) Wait(js string, params ...interface{}) {
 	utils.E(p.WaitE(Sleeper(), "", js, params))
@@ -330,6 +412,34 @@ func (p *Page) ObjectsToJSON(list []*proto.RuntimeRemoteObject) proto.JSON {
 	return proto.JSON{Result: gjson.Parse(result)}
 }
 
+// ConsoleMessages of the page, the capturing starts on the first call of the console related methods
+func (p *Page) ConsoleMessages() []*ConsoleMessage {
+	list, err := p.ConsoleMessagesE()
+	utils.E(err)
+	return list
+}
+
+// OnConsole calls the fn for every console message of the page
+func (p *Page) OnConsole(fn func(*ConsoleMessage)) (remove func()) {
+	remove, err := p.OnConsoleE(fn)
+	utils.E(err)
+	return remove
+}
+
+// OnException calls the fn for every uncaught exception of the page
+func (p *Page) OnException(fn func(*JSException)) (remove func()) {
+	remove, err := p.OnExceptionE(fn)
+	utils.E(err)
+	return remove
+}
+
+// FailOnException reports every uncaught exception of the page to the t as an error, such as the *testing.T
+func (p *Page) FailOnException(t TestingT) (remove func()) {
+	remove, err := p.FailOnExceptionE(t)
+	utils.E(err)
+	return remove
+}
+
 // ElementFromNode creates an Element from the node id
 func (p *Page) ElementFromNode(id proto.DOMNodeID) *Element {
 	el, err := p.ElementFromNodeE(id)
@@ -713,6 +823,11 @@ func (el *Element) Eval(js string, params ...interface{}) proto.JSON {
 	return res.Value
 }
 
+// Evaluate the opts with the element as the `this` and decode the result into the result
+func (el *Element) Evaluate(opts *EvalOptions, result interface{}) {
+	utils.E(el.EvaluateE(opts, result))
+}
+
 // Has an element that matches the css selector
 func (el *Element) Has(selector string) bool {
 	has, err := el.HasE(selector)
@@ -811,3 +926,127 @@ func (el *Element) ElementsByJS(js string, params ...interface{}) Elements {
 	utils.E(err)
 	return list
 }
+
+// Get a page from the pool, it blocks until a slot is available or the ctx is done.
+// The page must be put back via Pool.Put after use.
+func (pool *Pool) Get(ctx context.Context) *Page {
+	p, err := pool.GetE(ctx)
+	utils.E(err)
+	return p
+}
+
+// Put the page back to the pool, its cookies, storage and viewport will be reset.
+// If the slot reaches the max uses its browser will be recycled.
+func (pool *Pool) Put(p *Page) {
+	utils.E(pool.PutE(p))
+}
+
+// Close the idle slots of the pool, the slots in use will be closed when they are put back
+func (pool *Pool) Close() {
+	utils.E(pool.CloseE())
+}
+
+// Downloads starts to manage the downloads of the browser context
+func (b *Browser) Downloads(dir string) *DownloadManager {
+	m, err := b.DownloadsE(dir)
+	utils.E(err)
+	return m
+}
+
+// Downloads starts to manage the downloads of the page
+func (p *Page) Downloads(dir string) *DownloadManager {
+	m, err := p.DownloadsE(dir)
+	utils.E(err)
+	return m
+}
+
+// Wait returns a function to wait for the next download
+func (m *DownloadManager) Wait() func() *Download {
+	wait := m.WaitE(m.browser.ctx)
+	return func() *Download {
+		d, err := wait()
+		utils.E(err)
+		return d
+	}
+}
+
+// Close the manager
+func (m *DownloadManager) Close() {
+	utils.E(m.CloseE())
+}
+
+// SaveAs moves the completed download to the path
+func (d *Download) SaveAs(path string) {
+	utils.E(d.SaveAsE(path))
+}
+
+// Wait until the download is completed
+func (d *Download) Wait() {
+	utils.E(d.WaitE(d.manager.browser.ctx))
+}
+
+// Cancel the download
+func (d *Download) Cancel() {
+	utils.E(d.CancelE())
+}
+
+// Element waits until the element of the locator is attached, then returns it
+func (l *Locator) Element() *Element {
+	el, err := l.ElementE()
+	utils.E(err)
+	return el
+}
+
+// Has checks if the element of the locator is attached without waiting
+func (l *Locator) Has() bool {
+	has, err := l.HasE()
+	utils.E(err)
+	return has
+}
+
+// Click the element of the locator once it's visible, stable and enabled
+func (l *Locator) Click() *Locator {
+	utils.E(l.ClickE(proto.InputMouseButtonLeft))
+	return l
+}
+
+// Input the text to the element of the locator once it's visible, stable and enabled
+func (l *Locator) Input(text string) *Locator {
+	utils.E(l.InputE(text))
+	return l
+}
+
+// Text of the element of the locator
+func (l *Locator) Text() string {
+	text, err := l.TextE()
+	utils.E(err)
+	return text
+}
+
+// AccessibilityTree of the page, the ignored nodes are excluded
+func (p *Page) AccessibilityTree() *AXNode {
+	tree, err := p.AccessibilityTreeE()
+	utils.E(err)
+	return tree
+}
+
+// AXNode returns the node of the accessibility tree that the element backs
+func (el *Element) AXNode() *AXNode {
+	node, err := el.AXNodeE()
+	utils.E(err)
+	return node
+}
+
+// Element returns the element of the DOM node that backs the accessibility node
+func (n *AXNode) Element() *Element {
+	el, err := n.ElementE()
+	utils.E(err)
+	return el
+}
+
+// AuditAccessibility checks the page against the AccessibilityRules
+func (p *Page) AuditAccessibility() *AccessibilityReport {
+	report, err := p.AuditAccessibilityE()
+	utils.E(err)
+	return report
+}